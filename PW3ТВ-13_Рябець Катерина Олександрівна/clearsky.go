@@ -0,0 +1,175 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+	"time"
+)
+
+const (
+	solarConstant     = 1367.0 // Сонячна стала, Вт/м²
+	groundAlbedo      = 0.2    // Альбедо поверхні землі
+	performanceRatio  = 0.85   // Коефіцієнт продуктивності СЕС (втрати в інверторі, кабелях, нагрів)
+	stcIrradiance     = 1000.0 // Опромінення за стандартних умов, Вт/м²
+	forecastStepsHour = 4      // Кроків інтегрування на годину
+)
+
+// Параметри сонячної електростанції для прогнозу
+type pvPlant struct {
+	Latitude    float64 // Широта, °
+	Longitude   float64 // Довгота, ° (схід додатний)
+	UTCOffset   float64 // Зсув часового поясу, год
+	Tilt        float64 // Кут нахилу панелей, °
+	Azimuth     float64 // Азимут панелей, ° (180 = південь)
+	CapacityKWp float64 // Встановлена потужність, кВт піку
+}
+
+// Добовий прогноз потужності СЕС
+type pvForecast struct {
+	Hourly      [24]float64 // Середня потужність за кожну годину, МВт
+	DailyEnergy float64     // Добове виробництво, МВт·год
+	MeanPower   float64     // Середньодобова потужність Pc, МВт
+}
+
+// Положення Сонця: зенітний кут та азимут (від півночі за годинниковою стрілкою), радіани
+func solarPosition(p pvPlant, dayOfYear int, clockHour float64) (float64, float64) {
+	lat := p.Latitude * math.Pi / 180
+	b := 2 * math.Pi * float64(dayOfYear-81) / 365
+
+	// Схилення Сонця та рівняння часу (хв)
+	decl := 23.45 * math.Pi / 180 * math.Sin(2*math.Pi*float64(284+dayOfYear)/365)
+	eot := 9.87*math.Sin(2*b) - 7.53*math.Cos(b) - 1.5*math.Sin(b)
+
+	// Істинний сонячний час та годинний кут
+	solarTime := clockHour + (4*(p.Longitude-15*p.UTCOffset)+eot)/60
+	hourAngle := 15 * (solarTime - 12) * math.Pi / 180
+
+	cosZenith := math.Sin(lat)*math.Sin(decl) + math.Cos(lat)*math.Cos(decl)*math.Cos(hourAngle)
+	cosZenith = math.Max(-1, math.Min(1, cosZenith))
+	zenith := math.Acos(cosZenith)
+
+	azimuth := 0.0
+	if sinZenith := math.Sin(zenith); sinZenith > 1e-9 {
+		cosAz := (math.Sin(decl) - math.Sin(lat)*cosZenith) / (math.Cos(lat) * sinZenith)
+		azimuth = math.Acos(math.Max(-1, math.Min(1, cosAz)))
+		if hourAngle > 0 {
+			azimuth = 2*math.Pi - azimuth
+		}
+	}
+	return zenith, azimuth
+}
+
+// Опромінення площини панелей (Вт/м²) для ясного неба з поправкою на хмарність
+func planeOfArrayIrradiance(p pvPlant, zenith, azimuth float64, dayOfYear int, cloudiness float64) float64 {
+	cosZenith := math.Cos(zenith)
+	if cosZenith <= 0 {
+		return 0
+	}
+
+	// Глобальне горизонтальне опромінення за моделлю Гаурвіца та поправка Кастена-Чеплака на хмарність
+	ghi := 1098 * cosZenith * math.Exp(-0.059/cosZenith)
+	ghi *= 1 - 0.75*math.Pow(cloudiness, 3.4)
+
+	// Розділення на пряму та розсіяну складові (кореляція Ербса)
+	extraterrestrial := solarConstant * (1 + 0.033*math.Cos(2*math.Pi*float64(dayOfYear)/365)) * cosZenith
+	kt := math.Min(ghi/extraterrestrial, 1)
+	var diffuseFraction float64
+	switch {
+	case kt <= 0.22:
+		diffuseFraction = 1 - 0.09*kt
+	case kt <= 0.8:
+		diffuseFraction = 0.9511 - 0.1604*kt + 4.388*kt*kt - 16.638*math.Pow(kt, 3) + 12.336*math.Pow(kt, 4)
+	default:
+		diffuseFraction = 0.165
+	}
+	dhi := ghi * diffuseFraction
+	dni := (ghi - dhi) / cosZenith
+
+	// Кут падіння променів на панель
+	tilt := p.Tilt * math.Pi / 180
+	panelAz := p.Azimuth * math.Pi / 180
+	cosIncidence := cosZenith*math.Cos(tilt) + math.Sin(zenith)*math.Sin(tilt)*math.Cos(azimuth-panelAz)
+
+	beam := dni * math.Max(cosIncidence, 0)
+	diffuse := dhi * (1 + math.Cos(tilt)) / 2
+	reflected := ghi * groundAlbedo * (1 - math.Cos(tilt)) / 2
+	return beam + diffuse + reflected
+}
+
+// Погодинний прогноз потужності СЕС на задану дату
+func forecastPV(p pvPlant, date time.Time, cloudiness float64) (pvForecast, error) {
+	if p.CapacityKWp <= 0 {
+		return pvForecast{}, errors.New("встановлена потужність має бути додатною")
+	}
+	if p.Latitude < -90 || p.Latitude > 90 {
+		return pvForecast{}, errors.New("широта має бути в межах від -90° до 90°")
+	}
+	if cloudiness < 0 || cloudiness > 1 {
+		return pvForecast{}, errors.New("індекс хмарності має бути в межах від 0 до 1")
+	}
+
+	dayOfYear := date.YearDay()
+	var fc pvForecast
+	for h := 0; h < 24; h++ {
+		sum := 0.0
+		for s := 0; s < forecastStepsHour; s++ {
+			clockHour := float64(h) + (float64(s)+0.5)/forecastStepsHour
+			zenith, azimuth := solarPosition(p, dayOfYear, clockHour)
+			poa := planeOfArrayIrradiance(p, zenith, azimuth, dayOfYear, cloudiness)
+			sum += p.CapacityKWp / 1000 * poa / stcIrradiance * performanceRatio
+		}
+		fc.Hourly[h] = sum / forecastStepsHour
+		fc.DailyEnergy += fc.Hourly[h]
+	}
+	fc.MeanPower = fc.DailyEnergy / 24
+	return fc, nil
+}
+
+// Текстовий звіт прогнозу
+func formatForecast(fc pvForecast, date time.Time) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Прогноз ясного неба на %s:\n", date.Format("02.01.2006"))
+	fmt.Fprintf(&sb, "Добове виробництво: %.3f МВт·год\n", fc.DailyEnergy)
+	fmt.Fprintf(&sb, "Середньодобова потужність Pc: %.4f МВт\n", fc.MeanPower)
+	for h, power := range fc.Hourly {
+		if power > 0 {
+			fmt.Fprintf(&sb, "%02d:00-%02d:00  %.3f МВт\n", h, h+1, power)
+		}
+	}
+	return sb.String()
+}
+
+// SVG-графік погодинного профілю потужності
+func buildProfileSVG(fc pvForecast) template.HTML {
+	const width, height, pad = 460.0, 220.0, 30.0
+
+	maxY := 0.0
+	for _, power := range fc.Hourly {
+		maxY = math.Max(maxY, power)
+	}
+	if maxY == 0 {
+		maxY = 1
+	}
+	scaleX := func(h float64) float64 { return pad + h/24*(width-2*pad) }
+	scaleY := func(y float64) float64 { return height - pad - y/maxY*(height-2*pad) }
+
+	var points []string
+	for h, power := range fc.Hourly {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", scaleX(float64(h)+0.5), scaleY(power)))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f">`, width, height)
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="#ff4081" stroke-width="2"/>`, strings.Join(points, " "))
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#333"/>`, pad, height-pad, width-pad, height-pad)
+	for h := 0; h <= 24; h += 6 {
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.0f" font-size="11" text-anchor="middle">%d:00</text>`, scaleX(float64(h)), height-pad+15, h)
+	}
+	fmt.Fprintf(&sb, `<text x="%.0f" y="15" font-size="11">%.3f МВт</text>`, pad, maxY)
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
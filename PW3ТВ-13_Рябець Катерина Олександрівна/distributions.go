@@ -0,0 +1,182 @@
+package main
+
+import (
+	"errors"
+	"math"
+	"math/rand"
+)
+
+// Модель розподілу похибки прогнозу (похибка = факт - прогноз, МВт)
+type errorDistribution interface {
+	PDF(x float64) float64         // Щільність ймовірності похибки x
+	Sample(rng *rand.Rand) float64 // Випадкова похибка з цього розподілу
+}
+
+// Параметри моделі похибки, які задаються у формі
+type errorModelParams struct {
+	Model     string    // normal, laplace, studentt, skewnormal, kde
+	Bias      float64   // Зміщення похибки
+	StdDev    float64   // Середньоквадратичне відхилення
+	StudentNu float64   // Кількість ступенів свободи для t-розподілу
+	SkewAlpha float64   // Параметр асиметрії для skew-normal
+	Samples   []float64 // Виміряні похибки для ядерної оцінки щільності
+}
+
+// Нормальний розподіл
+type normalError struct {
+	Mean   float64
+	StdDev float64
+}
+
+func (d normalError) PDF(x float64) float64 {
+	return normalDistribution(x, d.Mean, d.StdDev)
+}
+
+func (d normalError) Sample(rng *rand.Rand) float64 {
+	return d.Mean + d.StdDev*rng.NormFloat64()
+}
+
+// Розподіл Лапласа з параметром масштабу b = σ/√2
+type laplaceError struct {
+	Mean  float64
+	Scale float64
+}
+
+func (d laplaceError) PDF(x float64) float64 {
+	return math.Exp(-math.Abs(x-d.Mean)/d.Scale) / (2 * d.Scale)
+}
+
+// Метод оберненої функції розподілу
+func (d laplaceError) Sample(rng *rand.Rand) float64 {
+	u := rng.Float64() - 0.5
+	if u < 0 {
+		return d.Mean + d.Scale*math.Log(1+2*u)
+	}
+	return d.Mean - d.Scale*math.Log(1-2*u)
+}
+
+// Масштабований t-розподіл Стьюдента
+type studentTError struct {
+	Mean  float64
+	Scale float64
+	Nu    float64
+}
+
+func (d studentTError) PDF(x float64) float64 {
+	z := (x - d.Mean) / d.Scale
+	lgA, _ := math.Lgamma((d.Nu + 1) / 2)
+	lgB, _ := math.Lgamma(d.Nu / 2)
+	norm := math.Exp(lgA-lgB) / (math.Sqrt(d.Nu*math.Pi) * d.Scale)
+	return norm * math.Pow(1+z*z/d.Nu, -(d.Nu+1)/2)
+}
+
+// t = Z / √(χ²/ν), де χ²(ν) = 2·Gamma(ν/2)
+func (d studentTError) Sample(rng *rand.Rand) float64 {
+	chi2 := 2 * sampleGamma(rng, d.Nu/2)
+	return d.Mean + d.Scale*rng.NormFloat64()/math.Sqrt(chi2/d.Nu)
+}
+
+// Скошений нормальний розподіл (skew-normal)
+type skewNormalError struct {
+	Location float64
+	Scale    float64
+	Alpha    float64
+}
+
+func (d skewNormalError) PDF(x float64) float64 {
+	z := (x - d.Location) / d.Scale
+	phi := math.Exp(-z*z/2) / math.Sqrt(2*math.Pi)
+	cdf := 0.5 * (1 + math.Erf(d.Alpha*z/math.Sqrt2))
+	return 2 / d.Scale * phi * cdf
+}
+
+// Представлення через дві незалежні нормальні величини
+func (d skewNormalError) Sample(rng *rand.Rand) float64 {
+	delta := d.Alpha / math.Sqrt(1+d.Alpha*d.Alpha)
+	u0 := math.Abs(rng.NormFloat64())
+	v := rng.NormFloat64()
+	return d.Location + d.Scale*(delta*u0+math.Sqrt(1-delta*delta)*v)
+}
+
+// Ядерна оцінка щільності за виміряними похибками (гаусове ядро)
+type kernelDensityError struct {
+	Samples   []float64
+	Bandwidth float64
+}
+
+func (d kernelDensityError) PDF(x float64) float64 {
+	sum := 0.0
+	for _, s := range d.Samples {
+		sum += normalDistribution(x, s, d.Bandwidth)
+	}
+	return sum / float64(len(d.Samples))
+}
+
+// Випадкова виміряна похибка, розмита ядром
+func (d kernelDensityError) Sample(rng *rand.Rand) float64 {
+	return d.Samples[rng.Intn(len(d.Samples))] + d.Bandwidth*rng.NormFloat64()
+}
+
+// Гамма-розподіл з одиничним масштабом (метод Марсальї-Цанга)
+func sampleGamma(rng *rand.Rand, shape float64) float64 {
+	if shape < 1 {
+		return sampleGamma(rng, shape+1) * math.Pow(rng.Float64(), 1/shape)
+	}
+	d := shape - 1.0/3
+	c := 1 / math.Sqrt(9*d)
+	for {
+		x := rng.NormFloat64()
+		v := 1 + c*x
+		if v <= 0 {
+			continue
+		}
+		v = v * v * v
+		u := rng.Float64()
+		if math.Log(u) < 0.5*x*x+d-d*v+d*math.Log(v) {
+			return d * v
+		}
+	}
+}
+
+// Створення моделі похибки; параметри підбираються так, щоб середнє і σ збігалися з заданими
+func newErrorDistribution(p errorModelParams) (errorDistribution, error) {
+	if p.StdDev <= 0 && p.Model != "kde" {
+		return nil, errors.New("σ має бути додатним")
+	}
+
+	switch p.Model {
+	case "", "normal":
+		return normalError{Mean: p.Bias, StdDev: p.StdDev}, nil
+	case "laplace":
+		return laplaceError{Mean: p.Bias, Scale: p.StdDev / math.Sqrt2}, nil
+	case "studentt":
+		if p.StudentNu <= 2 {
+			return nil, errors.New("для t-розподілу кількість ступенів свободи має бути більшою за 2")
+		}
+		return studentTError{
+			Mean:  p.Bias,
+			Scale: p.StdDev * math.Sqrt((p.StudentNu-2)/p.StudentNu),
+			Nu:    p.StudentNu,
+		}, nil
+	case "skewnormal":
+		delta := p.SkewAlpha / math.Sqrt(1+p.SkewAlpha*p.SkewAlpha)
+		scale := p.StdDev / math.Sqrt(1-2*delta*delta/math.Pi)
+		return skewNormalError{
+			Location: p.Bias - scale*delta*math.Sqrt(2/math.Pi),
+			Scale:    scale,
+			Alpha:    p.SkewAlpha,
+		}, nil
+	case "kde":
+		if len(p.Samples) < 2 {
+			return nil, errors.New("для ядерної оцінки щільності завантажте історичні дані")
+		}
+		if p.StdDev <= 0 {
+			return nil, errors.New("похибки прогнозу не мають розкиду, σ = 0")
+		}
+		// Ширина вікна за правилом Сільвермана
+		bandwidth := 1.06 * p.StdDev * math.Pow(float64(len(p.Samples)), -0.2)
+		return kernelDensityError{Samples: p.Samples, Bandwidth: bandwidth}, nil
+	default:
+		return nil, errors.New("невідома модель похибки: " + p.Model)
+	}
+}
@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"html/template"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Максимальний розмір завантажуваного файлу з історичними даними
+const maxUploadSize = 10 << 20
+
+// Кількість стовпців гістограми похибок
+const histogramBins = 20
+
+// Один запис історії: позначка часу, прогноз і фактична потужність
+type forecastRecord struct {
+	Timestamp string
+	Forecast  float64
+	Actual    float64
+}
+
+// Параметри розподілу похибки, оцінені за історичними даними
+type errorFit struct {
+	Bias   float64   // Зміщення (середня похибка факт - прогноз)
+	StdDev float64   // Середньоквадратичне відхилення похибки
+	Errors []float64 // Окремі похибки для гістограми
+}
+
+// Зчитування CSV з колонками: час, прогноз, факт (роздільник «,» або «;»)
+func parseForecastCSV(src io.Reader) ([]forecastRecord, error) {
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+	text := string(data)
+
+	reader := csv.NewReader(strings.NewReader(text))
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	if strings.Count(text, ";") > strings.Count(text, ",") {
+		reader.Comma = ';'
+	}
+
+	rows, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var records []forecastRecord
+	for i, row := range rows {
+		if len(row) < 3 {
+			return nil, fmt.Errorf("рядок %d: очікується 3 стовпці (час, прогноз, факт)", i+1)
+		}
+		forecast, err1 := strconv.ParseFloat(strings.TrimSpace(row[1]), 64)
+		actual, err2 := strconv.ParseFloat(strings.TrimSpace(row[2]), 64)
+		if err1 != nil || err2 != nil {
+			// Перший нечисловий рядок вважаємо заголовком
+			if i == 0 {
+				continue
+			}
+			return nil, fmt.Errorf("рядок %d: некоректні числові значення", i+1)
+		}
+		// ParseFloat приймає NaN та Inf, але вони зламають оцінку σ та гістограму
+		if math.IsNaN(forecast) || math.IsInf(forecast, 0) || math.IsNaN(actual) || math.IsInf(actual, 0) {
+			return nil, fmt.Errorf("рядок %d: значення мають бути скінченними числами", i+1)
+		}
+		records = append(records, forecastRecord{
+			Timestamp: strings.TrimSpace(row[0]),
+			Forecast:  forecast,
+			Actual:    actual,
+		})
+	}
+
+	if len(records) < 2 {
+		return nil, errors.New("для оцінки потрібно щонайменше 2 записи")
+	}
+	return records, nil
+}
+
+// Оцінка зміщення та σ похибки прогнозу (незміщена вибіркова дисперсія)
+func fitErrorDistribution(records []forecastRecord) errorFit {
+	errs := make([]float64, len(records))
+	sum := 0.0
+	for i, rec := range records {
+		errs[i] = rec.Actual - rec.Forecast
+		sum += errs[i]
+	}
+	bias := sum / float64(len(errs))
+
+	sumSq := 0.0
+	for _, e := range errs {
+		sumSq += (e - bias) * (e - bias)
+	}
+	stdDev := math.Sqrt(sumSq / float64(len(errs)-1))
+
+	return errorFit{Bias: bias, StdDev: stdDev, Errors: errs}
+}
+
+// Оцінка параметрів з файлу форми; nil, якщо файл не завантажено
+func fitFromUpload(r *http.Request, field string) (*errorFit, error) {
+	file, _, err := r.FormFile(field)
+	if err == http.ErrMissingFile || err == http.ErrNotMultipart {
+		// Файл не завантажено (або форму надіслано без multipart) — σ беруться з полів форми
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	records, err := parseForecastCSV(file)
+	if err != nil {
+		return nil, err
+	}
+	fit := fitErrorDistribution(records)
+	if !(fit.StdDev > 0) {
+		return nil, errors.New("похибки прогнозу не мають розкиду, σ = 0")
+	}
+	if math.IsInf(fit.StdDev, 0) || math.IsInf(fit.Bias, 0) {
+		return nil, errors.New("похибки прогнозу надто великі для оцінки σ")
+	}
+	return &fit, nil
+}
+
+// Побудова SVG-гістограми похибок разом із кривою підібраного нормального розподілу
+func buildHistogramSVG(fit errorFit) template.HTML {
+	const width, height, pad = 460.0, 220.0, 30.0
+
+	minErr, maxErr := fit.Errors[0], fit.Errors[0]
+	for _, e := range fit.Errors {
+		minErr = math.Min(minErr, e)
+		maxErr = math.Max(maxErr, e)
+	}
+	// Діапазон охоплює і дані, і ±3σ підібраної кривої
+	minX := math.Min(minErr, fit.Bias-3*fit.StdDev)
+	maxX := math.Max(maxErr, fit.Bias+3*fit.StdDev)
+	binWidth := (maxX - minX) / histogramBins
+
+	// Нормована гістограма (щільність), щоб порівнювати з кривою
+	counts := make([]float64, histogramBins)
+	for _, e := range fit.Errors {
+		idx := int((e - minX) / binWidth)
+		if idx >= histogramBins {
+			idx = histogramBins - 1
+		}
+		counts[idx]++
+	}
+	maxY := 0.0
+	for i := range counts {
+		counts[i] /= float64(len(fit.Errors)) * binWidth
+		maxY = math.Max(maxY, counts[i])
+	}
+	maxY = math.Max(maxY, normalDistribution(fit.Bias, fit.Bias, fit.StdDev))
+
+	scaleX := func(x float64) float64 { return pad + (x-minX)/(maxX-minX)*(width-2*pad) }
+	scaleY := func(y float64) float64 { return height - pad - y/maxY*(height-2*pad) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f">`, width, height)
+	for i, c := range counts {
+		x := scaleX(minX + float64(i)*binWidth)
+		y := scaleY(c)
+		fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="#f5c6d3" stroke="#ed95ad"/>`,
+			x, y, scaleX(minX+float64(i+1)*binWidth)-x, height-pad-y)
+	}
+
+	// Крива нормального розподілу
+	var points []string
+	for i := 0; i <= 100; i++ {
+		x := minX + float64(i)*(maxX-minX)/100
+		y := normalDistribution(x, fit.Bias, fit.StdDev)
+		points = append(points, fmt.Sprintf("%.1f,%.1f", scaleX(x), scaleY(y)))
+	}
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="#ff4081" stroke-width="2"/>`, strings.Join(points, " "))
+
+	// Вісь X з підписами меж
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#333"/>`, pad, height-pad, width-pad, height-pad)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11">%.2f</text>`, pad, height-pad+15, minX)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11" text-anchor="end">%.2f</text>`, width-pad, height-pad+15, maxX)
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
@@ -0,0 +1,133 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Найбільший горизонт розрахунку окупності, років (обмежує пам'ять сервера)
+const maxInvestmentHorizon = 100
+
+// Межі пошуку внутрішньої норми дохідності (частки одиниці)
+const (
+	irrMinRate = -0.99
+	irrMaxRate = 10.0
+)
+
+// Вхідні дані для оцінки окупності вдосконалення системи прогнозування
+type investmentParams struct {
+	CapitalCost   float64 // Капітальні витрати, тис. грн
+	OperatingCost float64 // Річні експлуатаційні витрати, тис. грн
+	DiscountRate  float64 // Ставка дисконтування, %
+	Horizon       int     // Горизонт розрахунку, років
+}
+
+// Показники окупності
+type investmentResult struct {
+	AnnualBenefit     float64 // Річний чистий ефект, тис. грн
+	NPV               float64 // Чиста приведена вартість, тис. грн
+	IRR               float64 // Внутрішня норма дохідності, %
+	IRRFound          bool
+	IRRAboveRange     bool    // NPV додатна в усьому діапазоні пошуку IRR
+	SimplePayback     float64 // Простий термін окупності, років
+	DiscountedPayback float64 // Дисконтований термін окупності, років
+}
+
+// Оцінка окупності за добовою різницею прибутку до та після вдосконалення
+func analyzeInvestment(dailyGain float64, p investmentParams) (investmentResult, error) {
+	if p.Horizon < 1 {
+		return investmentResult{}, errors.New("горизонт розрахунку має бути не менше 1 року")
+	}
+	if p.DiscountRate <= -100 {
+		return investmentResult{}, errors.New("ставка дисконтування має бути більшою за -100%")
+	}
+
+	res := investmentResult{AnnualBenefit: dailyGain*daysPerYear - p.OperatingCost}
+
+	// Грошові потоки: інвестиція у році 0, далі щорічний чистий ефект
+	flows := make([]float64, p.Horizon+1)
+	flows[0] = -p.CapitalCost
+	for t := 1; t <= p.Horizon; t++ {
+		flows[t] = res.AnnualBenefit
+	}
+
+	res.NPV = netPresentValue(flows, p.DiscountRate/100)
+	if irr, ok := internalRateOfReturn(flows); ok {
+		res.IRR, res.IRRFound = irr*100, true
+	} else {
+		res.IRRAboveRange = netPresentValue(flows, irrMaxRate) > 0
+	}
+	res.SimplePayback = paybackPeriod(flows, 0)
+	res.DiscountedPayback = paybackPeriod(flows, p.DiscountRate/100)
+
+	return res, nil
+}
+
+// Чиста приведена вартість потоків за ставкою rate
+func netPresentValue(flows []float64, rate float64) float64 {
+	npv := 0.0
+	for t, cf := range flows {
+		npv += cf / math.Pow(1+rate, float64(t))
+	}
+	return npv
+}
+
+// Внутрішня норма дохідності методом бісекції; false, якщо NPV не змінює знак
+func internalRateOfReturn(flows []float64) (float64, bool) {
+	low, high := irrMinRate, irrMaxRate
+	npvLow := netPresentValue(flows, low)
+	if npvLow*netPresentValue(flows, high) > 0 {
+		return 0, false
+	}
+	for i := 0; i < 200; i++ {
+		mid := (low + high) / 2
+		npvMid := netPresentValue(flows, mid)
+		if npvLow*npvMid <= 0 {
+			high = mid
+		} else {
+			low, npvLow = mid, npvMid
+		}
+	}
+	return (low + high) / 2, true
+}
+
+// Термін окупності з лінійною інтерполяцією всередині року; +Inf, якщо не окупається
+func paybackPeriod(flows []float64, rate float64) float64 {
+	cumulative := flows[0]
+	if cumulative >= 0 {
+		return 0
+	}
+	for t := 1; t < len(flows); t++ {
+		discounted := flows[t] / math.Pow(1+rate, float64(t))
+		if cumulative+discounted >= 0 {
+			return float64(t-1) + -cumulative/discounted
+		}
+		cumulative += discounted
+	}
+	return math.Inf(1)
+}
+
+// Текстовий звіт окупності
+func formatInvestmentResult(res investmentResult, p investmentParams) string {
+	irr := "не існує (NPV від'ємна в усьому діапазоні пошуку)"
+	if res.IRRFound {
+		irr = fmt.Sprintf("%.2f %%", res.IRR)
+	} else if res.IRRAboveRange {
+		irr = fmt.Sprintf("понад %.0f %% (NPV додатна в усьому діапазоні пошуку)", irrMaxRate*100)
+	}
+	return fmt.Sprintf(`Окупність вдосконалення (горизонт %d років):
+Річний чистий ефект: %.2f тис. грн
+NPV: %.2f тис. грн
+IRR: %s
+Простий термін окупності: %s
+Дисконтований термін окупності: %s`, p.Horizon, res.AnnualBenefit, res.NPV, irr,
+		formatPayback(res.SimplePayback, p.Horizon), formatPayback(res.DiscountedPayback, p.Horizon))
+}
+
+func formatPayback(years float64, horizon int) string {
+	if math.IsInf(years, 1) {
+		return fmt.Sprintf("не окупається за %d років", horizon)
+	}
+	return fmt.Sprintf("%.2f років", years)
+}
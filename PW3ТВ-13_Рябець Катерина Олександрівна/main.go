@@ -1,11 +1,14 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"html/template"
 	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Структура для збереження введених даних і результатів розрахунку
@@ -17,6 +20,60 @@ type PageData struct {
 	ResultBefore  string // До вдосконалення
 	ResultAfter   string // Після вдосконалення
 	ErrorMessage  string // Помилка
+
+	ErrorModel string // Модель розподілу похибки
+	StudentNu  string // Ступені свободи t-розподілу
+	SkewAlpha  string // Параметр асиметрії skew-normal
+
+	BandMode     string // Режим коридору: sigma, absolute, percent
+	BandWidth    string // Ширина коридору (МВт або %)
+	SurplusPrice string // Ціна штрафу за надлишок
+	DeficitPrice string // Ціна штрафу за дефіцит
+
+	Simulate         string        // Увімкнути моделювання Монте-Карло
+	SimYears         string        // Кількість змодельованих років
+	SimSeed          string        // Початкове значення генератора
+	SimulationResult string        // Статистика річного прибутку
+	ProfitHistogram  template.HTML // Гістограма річного прибутку
+
+	SolveSigma   string        // Увімкнути пошук σ беззбитковості
+	TargetProfit string        // Цільовий добовий прибуток, тис. грн
+	SolverResult string        // Знайдені значення σ
+	ProfitCurve  template.HTML // Графік прибуток-σ
+
+	CapitalCost      string // Капітальні витрати на вдосконалення
+	OperatingCost    string // Річні експлуатаційні витрати
+	DiscountRate     string // Ставка дисконтування, %
+	Horizon          string // Горизонт розрахунку, років
+	InvestmentResult string // Показники окупності
+
+	Generation string // Тип станції: solar або wind
+	PowerCurve string // Крива потужності ВЕС
+	WeibullK1  string // Параметр форми k поточного прогнозу вітру
+	WeibullC1  string // Параметр масштабу c поточного прогнозу вітру
+	WeibullK2  string // Параметр форми k вдосконаленого прогнозу вітру
+	WeibullC2  string // Параметр масштабу c вдосконаленого прогнозу вітру
+	WindResult string // Характеристики потужності ВЕС
+
+	UseForecast    string        // Розрахувати Pc за моделлю ясного неба
+	Latitude       string        // Широта СЕС
+	Longitude      string        // Довгота СЕС
+	UTCOffset      string        // Зсув часового поясу
+	Tilt           string        // Кут нахилу панелей
+	PanelAzimuth   string        // Азимут панелей
+	CapacityKWp    string        // Встановлена потужність, кВт піку
+	ForecastDate   string        // Дата прогнозу
+	Cloudiness     string        // Індекс хмарності
+	ForecastResult string        // Погодинний прогноз
+	ProfileChart   template.HTML // Графік профілю потужності
+
+	Plants          string // Список станцій портфеля
+	Correlation     string // Кореляційна матриця похибок
+	PortfolioResult string // Результат розрахунку портфеля
+
+	HistoryResult    string        // Параметри, оцінені за історичними даними
+	CurrentHistogram template.HTML // Гістограма похибок поточного прогнозу
+	FutureHistogram  template.HTML // Гістограма похибок вдосконаленого прогнозу
 }
 
 var tmpl *template.Template
@@ -32,7 +89,7 @@ func main() {
 
 	// Головна сторінка
 	http.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
-		tmpl.Execute(w, PageData{})
+		tmpl.Execute(w, defaultPageData())
 	})
 
 	// Обробка форми з розрахунками
@@ -43,15 +100,148 @@ func main() {
 	http.ListenAndServe(":8080", nil)
 }
 
+// Значення полів форми за замовчуванням
+func defaultPageData() PageData {
+	return PageData{
+		Generation:   "solar",
+		WeibullK1:    "2",
+		WeibullC1:    "8",
+		WeibullK2:    "4",
+		WeibullC2:    "8",
+		Latitude:     "50.45",
+		Longitude:    "30.52",
+		UTCOffset:    "3",
+		Tilt:         "35",
+		PanelAzimuth: "180",
+		ForecastDate: time.Now().Format("2006-01-02"),
+		Cloudiness:   "0",
+		ErrorModel:   "normal",
+		StudentNu:    "4",
+		SkewAlpha:    "0",
+		BandMode:     "sigma",
+		SimYears:     "1000",
+		SimSeed:      "42",
+		DiscountRate: "10",
+		Horizon:      "10",
+		TargetProfit: "0",
+	}
+}
+
 // Функція для розрахунку енергії
 func calculateEnergy(w http.ResponseWriter, r *http.Request) {
-	r.ParseForm()
+	if err := r.ParseMultipartForm(maxUploadSize); err != nil && err != http.ErrNotMultipart {
+		data := defaultPageData()
+		data.ErrorMessage = "Помилка читання форми: " + err.Error()
+		tmpl.Execute(w, data)
+		return
+	}
 
 	// Отримання значень з форми
 	dailyPower := r.FormValue("dailyPower")
 	currentStdDev := r.FormValue("currentStdDev")
 	futureStdDev := r.FormValue("futureStdDev")
 	energyCost := r.FormValue("energyCost")
+	errorModel := r.FormValue("errorModel")
+	studentNu := r.FormValue("studentNu")
+	skewAlpha := r.FormValue("skewAlpha")
+	bandMode := r.FormValue("bandMode")
+	bandWidth := r.FormValue("bandWidth")
+	surplusPrice := r.FormValue("surplusPrice")
+	deficitPrice := r.FormValue("deficitPrice")
+	simulate := r.FormValue("simulate")
+	simYears := r.FormValue("simYears")
+	simSeed := r.FormValue("simSeed")
+	generation := r.FormValue("generation")
+	useForecast := r.FormValue("useForecast")
+	solve := r.FormValue("solveSigma")
+	targetProfit := r.FormValue("targetProfit")
+	plantsText := r.FormValue("plants")
+	correlationText := r.FormValue("correlation")
+	capitalCost := r.FormValue("capitalCost")
+	operatingCost := r.FormValue("operatingCost")
+	discountRate := r.FormValue("discountRate")
+	horizon := r.FormValue("horizon")
+
+	data := PageData{
+		DailyPower:    dailyPower,
+		CurrentStdDev: currentStdDev,
+		FutureStdDev:  futureStdDev,
+		EnergyCost:    energyCost,
+		ErrorModel:    errorModel,
+		StudentNu:     studentNu,
+		SkewAlpha:     skewAlpha,
+		BandMode:      bandMode,
+		BandWidth:     bandWidth,
+		SurplusPrice:  surplusPrice,
+		DeficitPrice:  deficitPrice,
+		Simulate:      simulate,
+		SimYears:      simYears,
+		SimSeed:       simSeed,
+		Generation:    generation,
+		PowerCurve:    r.FormValue("powerCurve"),
+		WeibullK1:     r.FormValue("weibullK1"),
+		WeibullC1:     r.FormValue("weibullC1"),
+		WeibullK2:     r.FormValue("weibullK2"),
+		WeibullC2:     r.FormValue("weibullC2"),
+		UseForecast:   useForecast,
+		Latitude:      r.FormValue("latitude"),
+		Longitude:     r.FormValue("longitude"),
+		UTCOffset:     r.FormValue("utcOffset"),
+		Tilt:          r.FormValue("tilt"),
+		PanelAzimuth:  r.FormValue("panelAzimuth"),
+		CapacityKWp:   r.FormValue("capacityKWp"),
+		ForecastDate:  r.FormValue("forecastDate"),
+		Cloudiness:    r.FormValue("cloudiness"),
+		SolveSigma:    solve,
+		TargetProfit:  targetProfit,
+		Plants:        plantsText,
+		Correlation:   correlationText,
+		CapitalCost:   capitalCost,
+		OperatingCost: operatingCost,
+		DiscountRate:  discountRate,
+		Horizon:       horizon,
+	}
+
+	// Прогноз Pc за моделлю ясного неба замість введеного вручну значення
+	if useForecast != "" {
+		fc, date, err := forecastFromForm(data)
+		if err != nil {
+			data.ErrorMessage = "Помилка прогнозу: " + err.Error()
+			tmpl.Execute(w, data)
+			return
+		}
+		dailyPower = strconv.FormatFloat(fc.MeanPower, 'f', 4, 64)
+		data.DailyPower = dailyPower
+		data.ForecastResult = formatForecast(fc, date)
+		data.ProfileChart = buildProfileSVG(fc)
+		if fc.DailyEnergy <= 0 {
+			// Полярна ніч або сонце не освітлює площину панелей — коридору для розрахунку немає
+			data.ErrorMessage = fmt.Sprintf("На %s СЕС не генерує електроенергію (сонце не освітлює панелі), розрахунок прибутку не виконується.", date.Format("02.01.2006"))
+			tmpl.Execute(w, data)
+			return
+		}
+	}
+
+	// Вітровий режим: Pc та σ потужності визначаються кривою потужності та розподілом Вейбулла
+	var curve powerCurve
+	var wind1, wind2 weibullDist
+	var windBefore, windAfter windStats
+	if generation == "wind" {
+		var err error
+		curve, wind1, wind2, err = windFromForm(data)
+		if err != nil {
+			data.ErrorMessage = "Помилка вітрового режиму: " + err.Error()
+			tmpl.Execute(w, data)
+			return
+		}
+		windBefore = windPowerStats(curve, wind1)
+		windAfter = windPowerStats(curve, wind2)
+		dailyPower = strconv.FormatFloat(windAfter.MeanPower, 'f', 4, 64)
+		currentStdDev = strconv.FormatFloat(windBefore.StdDev, 'f', 4, 64)
+		futureStdDev = strconv.FormatFloat(windAfter.StdDev, 'f', 4, 64)
+		data.DailyPower, data.CurrentStdDev, data.FutureStdDev = dailyPower, currentStdDev, futureStdDev
+		data.WindResult = formatWindStats(windBefore, windAfter)
+	}
 
 	// Перевірка, чи всі поля заповнені
 	Pc, err1 := strconv.ParseFloat(dailyPower, 64)
@@ -59,72 +249,295 @@ func calculateEnergy(w http.ResponseWriter, r *http.Request) {
 	sigma2, err3 := strconv.ParseFloat(futureStdDev, 64)
 	V, err4 := strconv.ParseFloat(energyCost, 64)
 
+	// Оцінка σ1 та σ2 за завантаженими історичними даними (якщо є)
+	currentFit, errFit1 := fitFromUpload(r, "currentHistory")
+	futureFit, errFit2 := fitFromUpload(r, "futureHistory")
+	if errFit1 != nil || errFit2 != nil {
+		data.ErrorMessage = "Помилка у файлі історичних даних: "
+		if errFit1 != nil {
+			data.ErrorMessage += errFit1.Error()
+		} else {
+			data.ErrorMessage += errFit2.Error()
+		}
+		tmpl.Execute(w, data)
+		return
+	}
+
+	params1 := errorModelParams{Model: errorModel} // Модель похибки до вдосконалення
+	params2 := errorModelParams{Model: errorModel} // Модель похибки після вдосконалення
+	if currentFit != nil {
+		sigma1, params1.Bias, params1.Samples, err2 = currentFit.StdDev, currentFit.Bias, currentFit.Errors, nil
+		data.CurrentStdDev = strconv.FormatFloat(sigma1, 'f', 4, 64)
+		data.CurrentHistogram = buildHistogramSVG(*currentFit)
+		data.HistoryResult += fmt.Sprintf("Поточний прогноз (%d записів): зміщення %.4f МВт, σ1 = %.4f МВт\n",
+			len(currentFit.Errors), params1.Bias, sigma1)
+	}
+	if futureFit != nil {
+		sigma2, params2.Bias, params2.Samples, err3 = futureFit.StdDev, futureFit.Bias, futureFit.Errors, nil
+		data.FutureStdDev = strconv.FormatFloat(sigma2, 'f', 4, 64)
+		data.FutureHistogram = buildHistogramSVG(*futureFit)
+		data.HistoryResult += fmt.Sprintf("Вдосконалений прогноз (%d записів): зміщення %.4f МВт, σ2 = %.4f МВт\n",
+			len(futureFit.Errors), params2.Bias, sigma2)
+	}
+
 	if err1 != nil || err2 != nil || err3 != nil || err4 != nil {
-		tmpl.Execute(w, PageData{
-			DailyPower:    dailyPower,
-			CurrentStdDev: currentStdDev,
-			FutureStdDev:  futureStdDev,
-			EnergyCost:    energyCost,
-			ErrorMessage:  "Будь ласка, введіть правильні числові значення!",
-		})
+		data.ErrorMessage = "Будь ласка, введіть правильні числові значення!"
+		tmpl.Execute(w, data)
 		return
 	}
 
-	P_lower := Pc - sigma2 // Нижня межа
-	P_upper := Pc + sigma2 // Верхня межа
-
-	// Розрахунки до вдосконалення
-	deltaW1 := integrateNormalDistribution(Pc, sigma1, P_lower, P_upper) // Інтегрування
-	W1 := Pc * 24 * deltaW1                                              // Енергія без небалансів
-	profitBefore := W1 * V                                               // Прибуток від  енергії
-	W2 := Pc * 24 * (1 - deltaW1)                                        // Енергія з небалансами
-	penaltyBefore := W2 * V                                              // Штраф за небаланси
-	finalProfitBefore := profitBefore - penaltyBefore                    // Загальний прибуток до вдосконалення
-
-	// Розрахунки після вдосконалення
-	deltaW2 := integrateNormalDistribution(Pc, sigma2, P_lower, P_upper) // Інтегрування
-	W3 := Pc * 24 * deltaW2                                              // Енергія без небалансів
-	profitAfter := W3 * V                                                // Прибуток від  енергії
-	W4 := Pc * 24 * (1 - deltaW2)                                        // Енергія з небалансами
-	penaltyAfter := W4 * V                                               // Штраф за небаланси
-	finalProfitAfter := profitAfter - penaltyAfter                       // Загальний прибуток після вдосконалення
-
-	// Формування результату
-	resultBefore := fmt.Sprintf(`До вдосконалення системи:
-Частка енергії без небалансів: %.2f МВт·год
-Прибуток: %.2f тис. грн
-Штраф: %.2f тис. грн
-Загальний прибуток: %.2f тис. грн`, W1, profitBefore, penaltyBefore, finalProfitBefore)
-
-	resultAfter := fmt.Sprintf(`Після вдосконалення системи:
-Частка енергії без небалансів: %.2f МВт·год
-Прибуток: %.2f тис. грн
-Штраф: %.2f тис. грн
-Загальний прибуток: %.2f тис. грн`, W3, profitAfter, penaltyAfter, finalProfitAfter)
+	// Параметри форми розподілу (використовуються лише відповідними моделями)
+	nu, _ := strconv.ParseFloat(studentNu, 64)
+	alpha, _ := strconv.ParseFloat(skewAlpha, 64)
+	params1.StdDev, params1.StudentNu, params1.SkewAlpha = sigma1, nu, alpha
+	params2.StdDev, params2.StudentNu, params2.SkewAlpha = sigma2, nu, alpha
+
+	dist1, errDist1 := newErrorDistribution(params1)
+	dist2, errDist2 := newErrorDistribution(params2)
+	if errDist1 != nil || errDist2 != nil {
+		if errDist1 != nil {
+			data.ErrorMessage = errDist1.Error()
+		} else {
+			data.ErrorMessage = errDist2.Error()
+		}
+		tmpl.Execute(w, data)
+		return
+	}
+
+	// Правила ринку: якщо ціни штрафів не задані, використовується V
+	rules := marketRules{BandMode: bandMode, Price: V, SurplusPrice: V, DeficitPrice: V}
+	var errBand, errSurplus, errDeficit error
+	if bandMode == "absolute" || bandMode == "percent" {
+		rules.BandWidth, errBand = strconv.ParseFloat(bandWidth, 64)
+	}
+	if surplusPrice != "" {
+		rules.SurplusPrice, errSurplus = strconv.ParseFloat(surplusPrice, 64)
+	}
+	if deficitPrice != "" {
+		rules.DeficitPrice, errDeficit = strconv.ParseFloat(deficitPrice, 64)
+	}
+	if errBand != nil || errSurplus != nil || errDeficit != nil {
+		data.ErrorMessage = "Будь ласка, введіть правильні числові значення!"
+		tmpl.Execute(w, data)
+		return
+	}
+
+	lower, upper, err := rules.band(Pc, sigma2) // Межі коридору
+	if err != nil {
+		data.ErrorMessage = err.Error()
+		tmpl.Execute(w, data)
+		return
+	}
+
+	// Розрахунки до та після вдосконалення
+	before := evaluateDay(dist1, Pc, lower, upper, sigma1, rules)
+	after := evaluateDay(dist2, Pc, lower, upper, sigma2, rules)
+	if generation == "wind" {
+		// Коридор кожного прогнозу будується від його власної очікуваної потужності
+		lowerBefore, upperBefore, _ := rules.band(windBefore.MeanPower, sigma2)
+		before = evaluateWindDay(curve, wind1, windBefore, lowerBefore, upperBefore, rules)
+		after = evaluateWindDay(curve, wind2, windAfter, lower, upper, rules)
+	}
 
 	// Передача даних у шаблон
-	tmpl.Execute(w, PageData{
-		DailyPower:    dailyPower,
-		CurrentStdDev: currentStdDev,
-		FutureStdDev:  futureStdDev,
-		EnergyCost:    energyCost,
-		ResultBefore:  resultBefore,
-		ResultAfter:   resultAfter,
-	})
+	data.ResultBefore = formatDailyResult("До вдосконалення системи", before)
+	data.ResultAfter = formatDailyResult("Після вдосконалення системи", after)
+
+	// Пошук σ беззбитковості та σ для цільового прибутку
+	if solve != "" {
+		target, err := strconv.ParseFloat(targetProfit, 64)
+		if err != nil {
+			data.ErrorMessage = "Будь ласка, введіть правильний цільовий прибуток!"
+			tmpl.Execute(w, data)
+			return
+		}
+		profit := newProfitFunc(params2, Pc, lower, upper, rules)
+		low := 0.01 * (upper - lower) / 2
+		high := math.Max(3*sigma1, 5*(upper-lower)/2)
+		sol := solveBreakEven(profit, target, low, high)
+		marks := map[string]float64{"σ1": sigma1, "σ2": sigma2}
+		if sol.BreakEvenFound {
+			marks["0"] = sol.BreakEven
+		}
+		data.SolverResult = formatSigmaSolution(sol, target, low, high)
+		if generation == "wind" {
+			data.SolverResult += windModelNote(errorModel)
+		}
+		data.ProfitCurve = buildProfitCurveSVG(profit, low, high, marks)
+	}
+
+	// Портфель станцій з корельованими похибками
+	if strings.TrimSpace(plantsText) != "" {
+		plants, err := parsePlants(plantsText)
+		if err == nil {
+			var corr [][]float64
+			corr, err = parseCorrelationMatrix(correlationText, len(plants))
+			if err == nil {
+				var portfolio portfolioResult
+				model := errorModelParams{Model: errorModel, StudentNu: nu, SkewAlpha: alpha}
+				portfolio, err = evaluatePortfolio(plants, corr, rules, model)
+				if err == nil {
+					data.PortfolioResult = formatPortfolioResult(plants, portfolio)
+					if generation == "wind" {
+						data.PortfolioResult += windModelNote(portfolio.Model)
+					}
+				}
+			}
+		}
+		if err != nil {
+			data.ErrorMessage = "Помилка портфеля: " + err.Error()
+			tmpl.Execute(w, data)
+			return
+		}
+	}
+
+	// Окупність вдосконалення (якщо задані капітальні витрати)
+	if capitalCost != "" {
+		inv := investmentParams{}
+		var errCap, errOp, errRate, errHorizon error
+		inv.CapitalCost, errCap = strconv.ParseFloat(capitalCost, 64)
+		if operatingCost != "" {
+			inv.OperatingCost, errOp = strconv.ParseFloat(operatingCost, 64)
+		}
+		inv.DiscountRate, errRate = strconv.ParseFloat(discountRate, 64)
+		inv.Horizon, errHorizon = strconv.Atoi(horizon)
+		if errCap != nil || errOp != nil || errRate != nil || errHorizon != nil {
+			data.ErrorMessage = "Будь ласка, введіть правильні параметри інвестицій!"
+			tmpl.Execute(w, data)
+			return
+		}
+		if inv.Horizon > maxInvestmentHorizon {
+			data.ErrorMessage = fmt.Sprintf("Горизонт розрахунку не може перевищувати %d років!", maxInvestmentHorizon)
+			tmpl.Execute(w, data)
+			return
+		}
+		invResult, err := analyzeInvestment(after.Final-before.Final, inv)
+		if err != nil {
+			data.ErrorMessage = err.Error()
+			tmpl.Execute(w, data)
+			return
+		}
+		data.InvestmentResult = formatInvestmentResult(invResult, inv)
+	}
+
+	// Моделювання річного прибутку
+	if simulate != "" {
+		years, errYears := strconv.Atoi(simYears)
+		seed, errSeed := strconv.ParseInt(simSeed, 10, 64)
+		if errYears != nil || errSeed != nil || years < 1 {
+			data.ErrorMessage = "Кількість років моделювання та seed мають бути цілими числами!"
+			tmpl.Execute(w, data)
+			return
+		}
+		if years > maxSimYears {
+			data.ErrorMessage = fmt.Sprintf("Кількість років моделювання не може перевищувати %d!", maxSimYears)
+			tmpl.Execute(w, data)
+			return
+		}
+		sim := simulationParams{Years: years, Seed: seed}
+		simBefore := simulateAnnualProfit(dist1, Pc, lower, upper, rules, sim)
+		simAfter := simulateAnnualProfit(dist2, Pc, lower, upper, rules, sim)
+		data.SimulationResult = formatSimulationStats("Річний прибуток до вдосконалення", simBefore) +
+			"\n\n" + formatSimulationStats("Річний прибуток після вдосконалення", simAfter)
+		if generation == "wind" {
+			data.SimulationResult += windModelNote(errorModel)
+		}
+		data.ProfitHistogram = buildProfitHistogramSVG(simBefore, simAfter)
+	}
+
+	tmpl.Execute(w, data)
+}
+
+// Прогноз потужності СЕС за параметрами з форми
+func forecastFromForm(data PageData) (pvForecast, time.Time, error) {
+	var plant pvPlant
+	var cloudiness float64
+	fields := []struct {
+		value string
+		dest  *float64
+	}{
+		{data.Latitude, &plant.Latitude},
+		{data.Longitude, &plant.Longitude},
+		{data.UTCOffset, &plant.UTCOffset},
+		{data.Tilt, &plant.Tilt},
+		{data.PanelAzimuth, &plant.Azimuth},
+		{data.CapacityKWp, &plant.CapacityKWp},
+		{data.Cloudiness, &cloudiness},
+	}
+	for _, f := range fields {
+		v, err := strconv.ParseFloat(f.value, 64)
+		if err != nil {
+			return pvForecast{}, time.Time{}, errors.New("введіть правильні числові параметри СЕС")
+		}
+		*f.dest = v
+	}
+
+	date, err := time.Parse("2006-01-02", data.ForecastDate)
+	if err != nil {
+		return pvForecast{}, time.Time{}, errors.New("некоректна дата прогнозу")
+	}
+
+	fc, err := forecastPV(plant, date, cloudiness)
+	return fc, date, err
 }
 
-// Функція чисельного інтегрування нормального розподілу
-func integrateNormalDistribution(Pc, stdDev, P_lower, P_upper float64) float64 {
+// Крива потужності та прогнози швидкості вітру з форми
+func windFromForm(data PageData) (powerCurve, weibullDist, weibullDist, error) {
+	curve, err := parsePowerCurve(data.PowerCurve)
+	if err != nil {
+		return nil, weibullDist{}, weibullDist{}, err
+	}
+	k1, errK1 := strconv.ParseFloat(data.WeibullK1, 64)
+	c1, errC1 := strconv.ParseFloat(data.WeibullC1, 64)
+	k2, errK2 := strconv.ParseFloat(data.WeibullK2, 64)
+	c2, errC2 := strconv.ParseFloat(data.WeibullC2, 64)
+	if errK1 != nil || errC1 != nil || errK2 != nil || errC2 != nil {
+		return nil, weibullDist{}, weibullDist{}, errors.New("введіть правильні параметри розподілу Вейбулла")
+	}
+	wind1, err := newWeibullDist(k1, c1)
+	if err != nil {
+		return nil, weibullDist{}, weibullDist{}, err
+	}
+	wind2, err := newWeibullDist(k2, c2)
+	if err != nil {
+		return nil, weibullDist{}, weibullDist{}, err
+	}
+	return curve, wind1, wind2, nil
+}
+
+// Функція чисельного інтегрування щільності похибки на проміжку [lower, upper]
+func integrateDistribution(dist errorDistribution, lower, upper float64) float64 {
 	n := 1000 // Кількість кроків для інтегрування
-	step := (P_upper - P_lower) / float64(n)
+	step := (upper - lower) / float64(n)
+	area := 0.0
+
+	for i := 0; i < n; i++ {
+		x1 := lower + float64(i)*step
+		x2 := lower + float64(i+1)*step
+		area += 0.5 * (dist.PDF(x1) + dist.PDF(x2)) * step // Метод трапецій
+	}
+
+	return area
+}
+
+// Ймовірність похибки, меншої за upper; нескінченний проміжок зводиться до [0, 1)
+// заміною x = upper - scale·t/(1-t)
+func integrateBelow(dist errorDistribution, upper, scale float64) float64 {
+	n := 4000 // Кількість кроків для інтегрування
+	step := 1.0 / float64(n)
+	integrand := func(t float64) float64 {
+		if t >= 1 {
+			return 0
+		}
+		x := upper - scale*t/(1-t)
+		return dist.PDF(x) * scale / ((1 - t) * (1 - t))
+	}
 	area := 0.0
 
 	for i := 0; i < n; i++ {
-		x1 := P_lower + float64(i)*step
-		x2 := P_lower + float64(i+1)*step
-		y1 := normalDistribution(x1, Pc, stdDev)
-		y2 := normalDistribution(x2, Pc, stdDev)
-		area += 0.5 * (y1 + y2) * step // Метод трапецій
+		t1 := float64(i) * step
+		t2 := float64(i+1) * step
+		area += 0.5 * (integrand(t1) + integrand(t2)) * step // Метод трапецій
 	}
 
 	return area
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Правила ринку: коридор допустимого небалансу та ціни
+type marketRules struct {
+	BandMode     string  // sigma (±σ2), absolute (±МВт), percent (±% від прогнозу)
+	BandWidth    float64 // Ширина коридору для режимів absolute та percent
+	Price        float64 // Ціна енергії в межах коридору V
+	SurplusPrice float64 // Ціна штрафу за надлишок (факт вище коридору)
+	DeficitPrice float64 // Ціна штрафу за дефіцит (факт нижче коридору)
+}
+
+// Результат розрахунку за одну добу
+type dailyResult struct {
+	InShare       float64 // Частка енергії без небалансів
+	SurplusShare  float64 // Частка з надлишком
+	DeficitShare  float64 // Частка з дефіцитом
+	EnergyIn      float64 // Енергія без небалансів, МВт·год
+	EnergySurplus float64 // Енергія з надлишком, МВт·год
+	EnergyDeficit float64 // Енергія з дефіцитом, МВт·год
+	Profit        float64 // Прибуток, тис. грн
+	Penalty       float64 // Штраф, тис. грн
+	Final         float64 // Загальний прибуток, тис. грн
+}
+
+// Межі коридору відносно прогнозу Pc (у просторі похибки, МВт)
+func (m marketRules) band(Pc, sigma2 float64) (float64, float64, error) {
+	var half float64
+	switch m.BandMode {
+	case "", "sigma":
+		half = sigma2
+	case "absolute":
+		half = m.BandWidth
+	case "percent":
+		half = Pc * m.BandWidth / 100
+	default:
+		return 0, 0, errors.New("невідомий режим коридору: " + m.BandMode)
+	}
+	if half <= 0 {
+		return 0, 0, errors.New("ширина коридору має бути додатною")
+	}
+	return -half, half, nil
+}
+
+// Розрахунок прибутку та штрафів за добу з окремим ціноутворенням для кожної сторони розподілу
+func evaluateDay(dist errorDistribution, Pc, lower, upper, scale float64, m marketRules) dailyResult {
+	inShare := integrateDistribution(dist, lower, upper)
+	deficitShare := integrateBelow(dist, lower, scale)
+	surplusShare := 1 - inShare - deficitShare
+	if surplusShare < 0 {
+		surplusShare = 0
+	}
+
+	return dailyResultFromShares(Pc, inShare, surplusShare, deficitShare, m)
+}
+
+// Енергія, прибуток і штрафи за відомими частками
+func dailyResultFromShares(Pc, inShare, surplusShare, deficitShare float64, m marketRules) dailyResult {
+	res := dailyResult{
+		InShare:       inShare,
+		SurplusShare:  surplusShare,
+		DeficitShare:  deficitShare,
+		EnergyIn:      Pc * 24 * inShare,
+		EnergySurplus: Pc * 24 * surplusShare,
+		EnergyDeficit: Pc * 24 * deficitShare,
+	}
+	res.Profit = res.EnergyIn * m.Price
+	res.Penalty = res.EnergySurplus*m.SurplusPrice + res.EnergyDeficit*m.DeficitPrice
+	res.Final = res.Profit - res.Penalty
+	return res
+}
+
+// Текстовий звіт за добу
+func formatDailyResult(title string, res dailyResult) string {
+	return fmt.Sprintf(`%s:
+Частка енергії без небалансів: %.2f МВт·год
+Енергія з надлишком: %.2f МВт·год
+Енергія з дефіцитом: %.2f МВт·год
+Прибуток: %.2f тис. грн
+Штраф: %.2f тис. грн
+Загальний прибуток: %.2f тис. грн`, title, res.EnergyIn, res.EnergySurplus, res.EnergyDeficit,
+		res.Profit, res.Penalty, res.Final)
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Станція портфеля: прогноз та σ похибки
+type portfolioPlant struct {
+	Name   string
+	Pc     float64 // Середньодобова потужність, МВт
+	StdDev float64 // σ похибки прогнозу, МВт
+}
+
+// Результат розрахунку портфеля
+type portfolioResult struct {
+	Standalone    []dailyResult // Результати станцій окремо
+	StandaloneSum dailyResult   // Сума результатів окремих станцій
+	Portfolio     dailyResult   // Результат портфеля як єдиного учасника
+	TotalPc       float64       // Сумарний прогноз, МВт
+	StdDev        float64       // σ похибки портфеля, МВт
+	Model         string        // Модель похибки станцій і портфеля
+}
+
+// Зчитування списку станцій: кожен рядок «назва Pc σ» або «Pc σ»
+func parsePlants(text string) ([]portfolioPlant, error) {
+	var plants []portfolioPlant
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.ReplaceAll(line, ";", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		name := fmt.Sprintf("СЕС %d", len(plants)+1)
+		if len(fields) == 3 {
+			name, fields = fields[0], fields[1:]
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("рядок %d: очікується «назва Pc σ» або «Pc σ»", i+1)
+		}
+		pc, err1 := strconv.ParseFloat(fields[0], 64)
+		sigma, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 != nil || err2 != nil || sigma <= 0 {
+			return nil, fmt.Errorf("рядок %d: некоректні Pc або σ", i+1)
+		}
+		plants = append(plants, portfolioPlant{Name: name, Pc: pc, StdDev: sigma})
+	}
+	if len(plants) == 0 {
+		return nil, errors.New("список станцій порожній")
+	}
+	return plants, nil
+}
+
+// Зчитування кореляційної матриці n×n; порожній текст означає незалежні похибки
+func parseCorrelationMatrix(text string, n int) ([][]float64, error) {
+	corr := make([][]float64, n)
+	for i := range corr {
+		corr[i] = make([]float64, n)
+		corr[i][i] = 1
+	}
+	if strings.TrimSpace(text) == "" {
+		return corr, nil
+	}
+
+	row := 0
+	for _, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.ReplaceAll(line, ";", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		if row >= n || len(fields) != n {
+			return nil, fmt.Errorf("кореляційна матриця має бути розміром %d×%d", n, n)
+		}
+		for j, f := range fields {
+			v, err := strconv.ParseFloat(f, 64)
+			if err != nil || v < -1 || v > 1 {
+				return nil, fmt.Errorf("рядок %d: коефіцієнти кореляції мають бути в межах [-1, 1]", row+1)
+			}
+			corr[row][j] = v
+		}
+		row++
+	}
+	if row != n {
+		return nil, fmt.Errorf("кореляційна матриця має бути розміром %d×%d", n, n)
+	}
+
+	for i := 0; i < n; i++ {
+		if corr[i][i] != 1 {
+			return nil, errors.New("діагональ кореляційної матриці має складатися з одиниць")
+		}
+		for j := 0; j < i; j++ {
+			if corr[i][j] != corr[j][i] {
+				return nil, errors.New("кореляційна матриця має бути симетричною")
+			}
+		}
+	}
+	if !isPositiveSemidefinite(corr) {
+		return nil, errors.New("кореляційна матриця має бути невід'ємно визначеною")
+	}
+	return corr, nil
+}
+
+// Перевірка невід'ємної визначеності розкладом Холецького з малим допуском
+func isPositiveSemidefinite(a [][]float64) bool {
+	const eps = 1e-9
+	n := len(a)
+	l := make([][]float64, n)
+	for i := range l {
+		l[i] = make([]float64, n)
+	}
+	for i := 0; i < n; i++ {
+		for j := 0; j <= i; j++ {
+			sum := a[i][j]
+			for k := 0; k < j; k++ {
+				sum -= l[i][k] * l[j][k]
+			}
+			if i == j {
+				if sum < -eps {
+					return false
+				}
+				l[i][i] = math.Sqrt(math.Max(sum, 0))
+			} else if l[j][j] > eps {
+				l[i][j] = sum / l[j][j]
+			} else if math.Abs(sum) > eps {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Розрахунок портфеля з корельованими похибками; model задає форму розподілу,
+// σ кожної станції береться з її рядка
+func evaluatePortfolio(plants []portfolioPlant, corr [][]float64, m marketRules, model errorModelParams) (portfolioResult, error) {
+	// Виміряні похибки належать одній станції, тому для портфеля замість ядерної оцінки — нормальний розподіл
+	if model.Model == "kde" {
+		model = errorModelParams{Model: "normal"}
+	}
+	res := portfolioResult{Model: model.Model}
+
+	variance, scale := 0.0, 0.0
+	for i, a := range plants {
+		scale += a.StdDev * a.StdDev
+		for j, b := range plants {
+			variance += a.StdDev * b.StdDev * corr[i][j]
+		}
+	}
+	// Похибки, що повністю компенсують одна одну (кореляція −1), дають нульову дисперсію
+	// з точністю до округлення
+	if variance > 1e-12*scale {
+		res.StdDev = math.Sqrt(variance)
+	}
+
+	// Кожна станція окремо зі своїм коридором (у режимі ±σ — власна σ станції);
+	// коридор портфеля — сума коридорів станцій
+	halfSum := 0.0
+	for _, p := range plants {
+		lower, upper, err := m.band(p.Pc, p.StdDev)
+		if err != nil {
+			return portfolioResult{}, err
+		}
+		halfSum += upper
+		params := model
+		params.StdDev = p.StdDev
+		dist, err := newErrorDistribution(params)
+		if err != nil {
+			return portfolioResult{}, err
+		}
+		r := evaluateDay(dist, p.Pc, lower, upper, p.StdDev, m)
+		res.Standalone = append(res.Standalone, r)
+		res.TotalPc += p.Pc
+
+		res.StandaloneSum.EnergyIn += r.EnergyIn
+		res.StandaloneSum.EnergySurplus += r.EnergySurplus
+		res.StandaloneSum.EnergyDeficit += r.EnergyDeficit
+		res.StandaloneSum.Profit += r.Profit
+		res.StandaloneSum.Penalty += r.Penalty
+		res.StandaloneSum.Final += r.Final
+	}
+	if res.TotalPc > 0 {
+		totalEnergy := res.TotalPc * 24
+		res.StandaloneSum.InShare = res.StandaloneSum.EnergyIn / totalEnergy
+		res.StandaloneSum.SurplusShare = res.StandaloneSum.EnergySurplus / totalEnergy
+		res.StandaloneSum.DeficitShare = res.StandaloneSum.EnergyDeficit / totalEnergy
+	}
+
+	// Портфель як один учасник ринку; без розкиду похибки вся енергія в межах коридору.
+	// Сума корельованих похибок наближено описується тією ж моделлю з σ портфеля
+	if res.StdDev == 0 {
+		res.Portfolio = dailyResultFromShares(res.TotalPc, 1, 0, 0, m)
+	} else {
+		params := model
+		params.StdDev = res.StdDev
+		dist, err := newErrorDistribution(params)
+		if err != nil {
+			return portfolioResult{}, err
+		}
+		res.Portfolio = evaluateDay(dist, res.TotalPc, -halfSum, halfSum, res.StdDev, m)
+	}
+
+	return res, nil
+}
+
+// Текстовий звіт портфеля
+func formatPortfolioResult(plants []portfolioPlant, res portfolioResult) string {
+	var sb strings.Builder
+	sb.WriteString("Портфель станцій:\n")
+	for i, p := range plants {
+		r := res.Standalone[i]
+		fmt.Fprintf(&sb, "%s: Pc = %.2f МВт, σ = %.2f МВт, без небалансів %.1f %%, прибуток %.2f тис. грн\n",
+			p.Name, p.Pc, p.StdDev, r.InShare*100, r.Final)
+	}
+	fmt.Fprintf(&sb, "\nСума окремих станцій: без небалансів %.1f %%, небаланс %.2f МВт·год, прибуток %.2f тис. грн\n",
+		res.StandaloneSum.InShare*100, res.StandaloneSum.EnergySurplus+res.StandaloneSum.EnergyDeficit, res.StandaloneSum.Final)
+	fmt.Fprintf(&sb, "Портфель (Pc = %.2f МВт, σ = %.2f МВт): без небалансів %.1f %%, небаланс %.2f МВт·год, прибуток %.2f тис. грн\n",
+		res.TotalPc, res.StdDev, res.Portfolio.InShare*100, res.Portfolio.EnergySurplus+res.Portfolio.EnergyDeficit, res.Portfolio.Final)
+	fmt.Fprintf(&sb, "Ефект агрегації: %.2f тис. грн\n", res.Portfolio.Final-res.StandaloneSum.Final)
+	fmt.Fprintf(&sb, "Модель похибки: %s; похибку портфеля наближено описано тією ж моделлю з σ портфеля", errorModelNames[res.Model])
+	return sb.String()
+}
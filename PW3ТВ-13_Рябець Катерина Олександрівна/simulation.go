@@ -0,0 +1,154 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Кількість днів у змодельованому році
+const daysPerYear = 365
+
+// Найбільша кількість змодельованих років за один запит (обмежує час і пам'ять сервера)
+const maxSimYears = 10000
+
+// Параметри імітаційного моделювання
+type simulationParams struct {
+	Years int   // Кількість змодельованих років
+	Seed  int64 // Початкове значення генератора для відтворюваності
+}
+
+// Статистика річного прибутку за результатами моделювання
+type simulationStats struct {
+	Profits []float64 // Річні прибутки, тис. грн
+	Mean    float64
+	P5      float64
+	P50     float64
+	P95     float64
+}
+
+// Річний прибуток одного року: щодня одна випадкова похибка прогнозу
+func simulateYear(dist errorDistribution, Pc, lower, upper float64, m marketRules, rng *rand.Rand) float64 {
+	total := 0.0
+	for day := 0; day < daysPerYear; day++ {
+		e := dist.Sample(rng)
+		switch {
+		case e < lower:
+			total += dailyResultFromShares(Pc, 0, 0, 1, m).Final
+		case e > upper:
+			total += dailyResultFromShares(Pc, 0, 1, 0, m).Final
+		default:
+			total += dailyResultFromShares(Pc, 1, 0, 0, m).Final
+		}
+	}
+	return total
+}
+
+// Моделювання річного прибутку методом Монте-Карло
+func simulateAnnualProfit(dist errorDistribution, Pc, lower, upper float64, m marketRules, p simulationParams) simulationStats {
+	rng := rand.New(rand.NewSource(p.Seed))
+	profits := make([]float64, p.Years)
+	sum := 0.0
+	for i := range profits {
+		profits[i] = simulateYear(dist, Pc, lower, upper, m, rng)
+		sum += profits[i]
+	}
+
+	sorted := append([]float64(nil), profits...)
+	sort.Float64s(sorted)
+
+	return simulationStats{
+		Profits: profits,
+		Mean:    sum / float64(len(profits)),
+		P5:      percentile(sorted, 5),
+		P50:     percentile(sorted, 50),
+		P95:     percentile(sorted, 95),
+	}
+}
+
+// Процентиль відсортованої вибірки з лінійною інтерполяцією
+func percentile(sorted []float64, q float64) float64 {
+	if len(sorted) == 1 {
+		return sorted[0]
+	}
+	pos := q / 100 * float64(len(sorted)-1)
+	i := int(pos)
+	if i >= len(sorted)-1 {
+		return sorted[len(sorted)-1]
+	}
+	frac := pos - float64(i)
+	return sorted[i] + frac*(sorted[i+1]-sorted[i])
+}
+
+// Текстовий звіт моделювання
+func formatSimulationStats(title string, s simulationStats) string {
+	return fmt.Sprintf(`%s (%d років):
+Середній річний прибуток: %.2f тис. грн
+5-й процентиль: %.2f тис. грн
+Медіана: %.2f тис. грн
+95-й процентиль: %.2f тис. грн`, title, len(s.Profits), s.Mean, s.P5, s.P50, s.P95)
+}
+
+// SVG-гістограма річного прибутку до та після вдосконалення
+func buildProfitHistogramSVG(before, after simulationStats) template.HTML {
+	const width, height, pad = 460.0, 220.0, 30.0
+
+	minX, maxX := math.Inf(1), math.Inf(-1)
+	for _, s := range []simulationStats{before, after} {
+		for _, v := range s.Profits {
+			minX = math.Min(minX, v)
+			maxX = math.Max(maxX, v)
+		}
+	}
+	if maxX == minX {
+		maxX = minX + 1
+	}
+	binWidth := (maxX - minX) / histogramBins
+
+	countBins := func(values []float64) []float64 {
+		counts := make([]float64, histogramBins)
+		for _, v := range values {
+			idx := int((v - minX) / binWidth)
+			if idx >= histogramBins {
+				idx = histogramBins - 1
+			}
+			counts[idx]++
+		}
+		return counts
+	}
+	countsBefore := countBins(before.Profits)
+	countsAfter := countBins(after.Profits)
+
+	maxY := 0.0
+	for i := 0; i < histogramBins; i++ {
+		maxY = math.Max(maxY, math.Max(countsBefore[i], countsAfter[i]))
+	}
+
+	scaleX := func(x float64) float64 { return pad + (x-minX)/(maxX-minX)*(width-2*pad) }
+	scaleY := func(y float64) float64 { return height - pad - y/maxY*(height-2*pad) }
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f">`, width, height)
+	drawBars := func(counts []float64, fill string) {
+		for i, c := range counts {
+			x := scaleX(minX + float64(i)*binWidth)
+			y := scaleY(c)
+			fmt.Fprintf(&sb, `<rect x="%.1f" y="%.1f" width="%.1f" height="%.1f" fill="%s" fill-opacity="0.6"/>`,
+				x, y, scaleX(minX+float64(i+1)*binWidth)-x, height-pad-y, fill)
+		}
+	}
+	drawBars(countsBefore, "#9e9e9e")
+	drawBars(countsAfter, "#ed95ad")
+
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#333"/>`, pad, height-pad, width-pad, height-pad)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11">%.0f</text>`, pad, height-pad+15, minX)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11" text-anchor="end">%.0f</text>`, width-pad, height-pad+15, maxX)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="15" font-size="11" fill="#9e9e9e">■ до вдосконалення</text>`, pad)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="15" font-size="11" fill="#ed95ad" text-anchor="end">■ після вдосконалення</text>`, width-pad)
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"fmt"
+	"html/template"
+	"math"
+	"strings"
+)
+
+// Кількість точок на графіку прибуток-σ
+const curvePoints = 60
+
+// Функція прибутку за добу залежно від σ похибки при фіксованому коридорі
+type profitFunc func(sigma float64) float64
+
+// Результат пошуку σ
+type sigmaSolution struct {
+	BreakEven      float64 // σ, за якого загальний прибуток дорівнює нулю
+	BreakEvenFound bool
+	Target         float64 // σ, потрібне для досягнення цільового прибутку
+	TargetFound    bool
+}
+
+// Параметри моделі похибки з іншим σ (для ядерної оцінки похибки масштабуються навколо зміщення)
+func withStdDev(p errorModelParams, sigma float64) errorModelParams {
+	if p.Model == "kde" && p.StdDev > 0 {
+		scaled := make([]float64, len(p.Samples))
+		for i, s := range p.Samples {
+			scaled[i] = p.Bias + (s-p.Bias)*sigma/p.StdDev
+		}
+		p.Samples = scaled
+	}
+	p.StdDev = sigma
+	return p
+}
+
+// Побудова функції прибутку для заданої моделі похибки та правил ринку
+func newProfitFunc(p errorModelParams, Pc, lower, upper float64, m marketRules) profitFunc {
+	return func(sigma float64) float64 {
+		dist, err := newErrorDistribution(withStdDev(p, sigma))
+		if err != nil {
+			return math.NaN()
+		}
+		return evaluateDay(dist, Pc, lower, upper, sigma, m).Final
+	}
+}
+
+// Пошук σ на проміжку [low, high], за якого profit(σ) = target (метод бісекції)
+func solveSigma(profit profitFunc, target, low, high float64) (float64, bool) {
+	fLow := profit(low) - target
+	fHigh := profit(high) - target
+	if math.IsNaN(fLow) || math.IsNaN(fHigh) || fLow*fHigh > 0 {
+		return 0, false
+	}
+	for i := 0; i < 100; i++ {
+		mid := (low + high) / 2
+		fMid := profit(mid) - target
+		if fLow*fMid <= 0 {
+			high = mid
+		} else {
+			low, fLow = mid, fMid
+		}
+		if high-low < 1e-9 {
+			break
+		}
+	}
+	return (low + high) / 2, true
+}
+
+// Пошук σ беззбитковості та σ для цільового прибутку
+func solveBreakEven(profit profitFunc, target, low, high float64) sigmaSolution {
+	var sol sigmaSolution
+	sol.BreakEven, sol.BreakEvenFound = solveSigma(profit, 0, low, high)
+	sol.Target, sol.TargetFound = solveSigma(profit, target, low, high)
+	return sol
+}
+
+// Текстовий звіт розв'язувача
+func formatSigmaSolution(sol sigmaSolution, target, low, high float64) string {
+	breakEven := fmt.Sprintf("не знайдено на проміжку [%.4f; %.4f] МВт", low, high)
+	if sol.BreakEvenFound {
+		breakEven = fmt.Sprintf("%.4f МВт", sol.BreakEven)
+	}
+	targetSigma := fmt.Sprintf("не досяжний на проміжку [%.4f; %.4f] МВт", low, high)
+	if sol.TargetFound {
+		targetSigma = fmt.Sprintf("%.4f МВт", sol.Target)
+	}
+	return fmt.Sprintf(`Пошук точності прогнозу:
+σ беззбитковості (прибуток = 0): %s
+σ для цільового прибутку %.2f тис. грн: %s`, breakEven, target, targetSigma)
+}
+
+// SVG-графік залежності прибутку від σ з позначками σ1, σ2 та знайдених розв'язків
+func buildProfitCurveSVG(profit profitFunc, low, high float64, marks map[string]float64) template.HTML {
+	const width, height, pad = 460.0, 240.0, 35.0
+
+	xs := make([]float64, curvePoints+1)
+	ys := make([]float64, curvePoints+1)
+	minY, maxY := math.Inf(1), math.Inf(-1)
+	for i := range xs {
+		xs[i] = low + float64(i)*(high-low)/curvePoints
+		ys[i] = profit(xs[i])
+		minY = math.Min(minY, ys[i])
+		maxY = math.Max(maxY, ys[i])
+	}
+	minY = math.Min(minY, 0)
+	maxY = math.Max(maxY, 0)
+	if maxY == minY {
+		maxY = minY + 1
+	}
+
+	scaleX := func(x float64) float64 { return pad + (x-low)/(high-low)*(width-2*pad) }
+	scaleY := func(y float64) float64 { return height - pad - (y-minY)/(maxY-minY)*(height-2*pad) }
+
+	var points []string
+	for i := range xs {
+		points = append(points, fmt.Sprintf("%.1f,%.1f", scaleX(xs[i]), scaleY(ys[i])))
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f">`, width, height)
+	// Лінія нульового прибутку
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.1f" x2="%.0f" y2="%.1f" stroke="#999" stroke-dasharray="4"/>`,
+		pad, scaleY(0), width-pad, scaleY(0))
+	fmt.Fprintf(&sb, `<polyline points="%s" fill="none" stroke="#ff4081" stroke-width="2"/>`, strings.Join(points, " "))
+	for label, x := range marks {
+		if x < low || x > high {
+			continue
+		}
+		fmt.Fprintf(&sb, `<line x1="%.1f" y1="%.0f" x2="%.1f" y2="%.0f" stroke="#ed95ad"/>`, scaleX(x), pad, scaleX(x), height-pad)
+		fmt.Fprintf(&sb, `<text x="%.1f" y="%.0f" font-size="11" text-anchor="middle">%s</text>`, scaleX(x), pad-5, template.HTMLEscapeString(label))
+	}
+	fmt.Fprintf(&sb, `<line x1="%.0f" y1="%.0f" x2="%.0f" y2="%.0f" stroke="#333"/>`, pad, height-pad, width-pad, height-pad)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11">σ = %.3f</text>`, pad, height-pad+15, low)
+	fmt.Fprintf(&sb, `<text x="%.0f" y="%.0f" font-size="11" text-anchor="end">σ = %.3f</text>`, width-pad, height-pad+15, high)
+	fmt.Fprintf(&sb, `<text x="2" y="%.0f" font-size="11">%.0f</text>`, scaleY(maxY)+4, maxY)
+	fmt.Fprintf(&sb, `<text x="2" y="%.0f" font-size="11">%.0f</text>`, scaleY(minY), minY)
+	sb.WriteString(`</svg>`)
+
+	return template.HTML(sb.String())
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Кількість кроків інтегрування за швидкістю вітру
+const windSteps = 4000
+
+// Точка кривої потужності вітрової турбіни
+type powerCurvePoint struct {
+	Speed float64 // Швидкість вітру, м/с
+	Power float64 // Потужність, МВт
+}
+
+// Крива потужності (упорядкована за швидкістю)
+type powerCurve []powerCurvePoint
+
+// Розподіл Вейбулла для прогнозу швидкості вітру
+type weibullDist struct {
+	Shape float64 // k
+	Scale float64 // c, м/с
+}
+
+// Характеристики вихідної потужності ВЕС для одного прогнозу
+type windStats struct {
+	MeanPower float64 // Очікувана потужність (прогноз Pc), МВт
+	StdDev    float64 // σ потужності, МВт
+}
+
+// Зчитування кривої потужності: кожен рядок «швидкість потужність»
+func parsePowerCurve(text string) (powerCurve, error) {
+	var curve powerCurve
+	for i, line := range strings.Split(text, "\n") {
+		fields := strings.Fields(strings.ReplaceAll(line, ";", " "))
+		if len(fields) == 0 {
+			continue
+		}
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("рядок %d: очікується «швидкість потужність»", i+1)
+		}
+		speed, err1 := strconv.ParseFloat(fields[0], 64)
+		power, err2 := strconv.ParseFloat(fields[1], 64)
+		if err1 != nil || err2 != nil || speed < 0 || power < 0 {
+			return nil, fmt.Errorf("рядок %d: некоректні швидкість або потужність", i+1)
+		}
+		curve = append(curve, powerCurvePoint{Speed: speed, Power: power})
+	}
+	if len(curve) < 2 {
+		return nil, errors.New("крива потужності має містити щонайменше 2 точки")
+	}
+	sort.Slice(curve, func(i, j int) bool { return curve[i].Speed < curve[j].Speed })
+	return curve, nil
+}
+
+// Потужність за кривою з лінійною інтерполяцією; поза кривою (до пуску та після зупинки) нуль
+func (c powerCurve) power(speed float64) float64 {
+	if speed < c[0].Speed || speed > c[len(c)-1].Speed {
+		return 0
+	}
+	i := sort.Search(len(c), func(i int) bool { return c[i].Speed >= speed })
+	if c[i].Speed == speed || i == 0 {
+		return c[i].Power
+	}
+	a, b := c[i-1], c[i]
+	return a.Power + (b.Power-a.Power)*(speed-a.Speed)/(b.Speed-a.Speed)
+}
+
+func (d weibullDist) pdf(v float64) float64 {
+	if v < 0 {
+		return 0
+	}
+	z := v / d.Scale
+	return d.Shape / d.Scale * math.Pow(z, d.Shape-1) * math.Exp(-math.Pow(z, d.Shape))
+}
+
+// Верхня межа інтегрування: кінець кривої або швидкість, вище якої ймовірність нехтовно мала
+func (d weibullDist) upperSpeed(c powerCurve) float64 {
+	return math.Max(c[len(c)-1].Speed, d.Scale*math.Pow(-math.Log(1e-9), 1/d.Shape))
+}
+
+// Інтеграл g(v)·f(v) за швидкістю вітру (метод трапецій) плюс залишок ймовірності з нульовою потужністю
+func (d weibullDist) expect(c powerCurve, g func(power float64) float64) float64 {
+	vMax := d.upperSpeed(c)
+	step := vMax / windSteps
+	area := 0.0
+	for i := 0; i < windSteps; i++ {
+		v1 := float64(i) * step
+		v2 := float64(i+1) * step
+		area += 0.5 * (g(c.power(v1))*d.pdf(v1) + g(c.power(v2))*d.pdf(v2)) * step
+	}
+	tail := math.Exp(-math.Pow(vMax/d.Scale, d.Shape))
+	return area + tail*g(0)
+}
+
+// Очікувана потужність та її σ
+func windPowerStats(c powerCurve, d weibullDist) windStats {
+	mean := d.expect(c, func(p float64) float64 { return p })
+	variance := d.expect(c, func(p float64) float64 { return (p - mean) * (p - mean) })
+	return windStats{MeanPower: mean, StdDev: math.Sqrt(math.Max(variance, 0))}
+}
+
+// Прибуток ВЕС за добу: прогноз Pc = очікувана потужність, коридор задається відносно нього
+func evaluateWindDay(c powerCurve, d weibullDist, s windStats, lower, upper float64, m marketRules) dailyResult {
+	indicator := func(cond func(e float64) bool) func(p float64) float64 {
+		return func(p float64) float64 {
+			if cond(p - s.MeanPower) {
+				return 1
+			}
+			return 0
+		}
+	}
+	inShare := d.expect(c, indicator(func(e float64) bool { return e >= lower && e <= upper }))
+	deficitShare := d.expect(c, indicator(func(e float64) bool { return e < lower }))
+	surplusShare := math.Max(1-inShare-deficitShare, 0)
+	return dailyResultFromShares(s.MeanPower, inShare, surplusShare, deficitShare, m)
+}
+
+// Перевірка параметрів розподілу Вейбулла
+func newWeibullDist(shape, scale float64) (weibullDist, error) {
+	if shape <= 0 || scale <= 0 {
+		return weibullDist{}, errors.New("параметри розподілу Вейбулла k та c мають бути додатними")
+	}
+	// При k < 1 щільність необмежена в нулі, і інтегрування методом трапецій розходиться
+	if shape < 1 {
+		return weibullDist{}, errors.New("параметр форми розподілу Вейбулла k має бути не меншим за 1")
+	}
+	return weibullDist{Shape: shape, Scale: scale}, nil
+}
+
+// Текстовий звіт вітрового режиму
+func formatWindStats(before, after windStats) string {
+	return fmt.Sprintf(`Вітрова електростанція:
+Поточний прогноз: Pc = %.4f МВт, σ потужності = %.4f МВт
+Вдосконалений прогноз: Pc = %.4f МВт, σ потужності = %.4f МВт
+Для моделювання, пошуку σ та портфеля використовується обрана модель похибки з цими σ.`,
+		before.MeanPower, before.StdDev, after.MeanPower, after.StdDev)
+}
+
+// Назви моделей похибки для приміток звіту
+var errorModelNames = map[string]string{
+	"":           "нормальний розподіл",
+	"normal":     "нормальний розподіл",
+	"laplace":    "розподіл Лапласа",
+	"studentt":   "t-розподіл Стьюдента",
+	"skewnormal": "скошений нормальний розподіл",
+	"kde":        "емпіричний розподіл",
+}
+
+// Примітка для розрахунків, які у вітровому режимі не використовують розподіл Вейбулла
+func windModelNote(model string) string {
+	return "\nУвага: у вітровому режимі цей розрахунок виконано за моделлю похибки «" + errorModelNames[model] +
+		"» з σ потужності ВЕС, а не безпосередньо за розподілом Вейбулла швидкості вітру."
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+)
+
+// Точка таблиці поправкових коефіцієнтів: значення параметра та коефіцієнт
+type FactorPoint struct {
+	X float64 `json:"x"`
+	K float64 `json:"k"`
+}
+
+// Поправки на температуру для одного способу прокладання
+type TemperatureFactors struct {
+	Reference float64       `json:"reference"`
+	Points    []FactorPoint `json:"points"`
+}
+
+// Таблиці поправкових коефіцієнтів до допустимого струму (файл correction_factors.json)
+type CorrectionFactorsData struct {
+	Temperature     map[string]TemperatureFactors `json:"temperature"`
+	SoilResistivity []FactorPoint                 `json:"soil_resistivity"`
+	Grouping        []FactorPoint                 `json:"grouping"`
+	Laying          map[string]float64            `json:"laying"`
+}
+
+// Умови прокладання кабелю
+type InstallationConditions struct {
+	Laying          string  // ground, duct, air
+	AmbientTemp     float64 // Температура ґрунту або повітря, °C
+	SoilResistivity float64 // Питомий тепловий опір ґрунту, К·м/Вт
+	CablesCount     int     // Кількість кабелів, прокладених поруч
+}
+
+// Окремі поправкові коефіцієнти та їх добуток
+type CorrectionFactors struct {
+	Temperature float64
+	Soil        float64
+	Grouping    float64
+	Laying      float64
+	Total       float64
+}
+
+var allCorrectionFactors CorrectionFactorsData
+
+// Зчитування файлу correction_factors.json та десеріалізація
+func loadCorrectionFactors(filename string) (CorrectionFactorsData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return CorrectionFactorsData{}, err
+	}
+	defer file.Close()
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		return CorrectionFactorsData{}, err
+	}
+	var factors CorrectionFactorsData
+	err = json.Unmarshal(bytes, &factors)
+	if err != nil {
+		return CorrectionFactorsData{}, err
+	}
+	return factors, nil
+}
+
+// Лінійна інтерполяція коефіцієнта за таблицею (за межами таблиці — крайнє значення)
+func interpolateFactor(points []FactorPoint, x float64) float64 {
+	if len(points) == 0 {
+		return 1.0
+	}
+	sorted := append([]FactorPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].X < sorted[j].X })
+
+	if x <= sorted[0].X {
+		return sorted[0].K
+	}
+	for i := 1; i < len(sorted); i++ {
+		if x <= sorted[i].X {
+			a, b := sorted[i-1], sorted[i]
+			return a.K + (b.K-a.K)*(x-a.X)/(b.X-a.X)
+		}
+	}
+	return sorted[len(sorted)-1].K
+}
+
+// Розрахунок поправкових коефіцієнтів для умов прокладання
+func calculateCorrectionFactors(
+	conditions InstallationConditions,
+	data CorrectionFactorsData,
+) (CorrectionFactors, error) {
+	laying, ok := data.Laying[conditions.Laying]
+	if !ok {
+		return CorrectionFactors{}, fmt.Errorf("невідомий спосіб прокладання: %s", conditions.Laying)
+	}
+	temperature, ok := data.Temperature[conditions.Laying]
+	if !ok {
+		return CorrectionFactors{}, fmt.Errorf("немає температурних поправок для способу прокладання: %s", conditions.Laying)
+	}
+	if conditions.CablesCount < 1 {
+		return CorrectionFactors{}, fmt.Errorf("кількість кабелів має бути не менше 1")
+	}
+
+	f := CorrectionFactors{
+		Temperature: interpolateFactor(temperature.Points, conditions.AmbientTemp),
+		Soil:        1.0,
+		Grouping:    interpolateFactor(data.Grouping, float64(conditions.CablesCount)),
+		Laying:      laying,
+	}
+	// Тепловий опір ґрунту не впливає на кабелі в повітрі
+	if conditions.Laying != "air" {
+		f.Soil = interpolateFactor(data.SoilResistivity, conditions.SoilResistivity)
+	}
+	f.Total = f.Temperature * f.Soil * f.Grouping * f.Laying
+	return f, nil
+}
+
+// Текстовий опис поправкових коефіцієнтів
+func describeCorrectionFactors(conditions InstallationConditions, f CorrectionFactors) string {
+	return fmt.Sprintf(
+		"Поправкові коефіцієнти (прокладання: %s):\n"+
+			"  kt (температура %.1f °C) = %.3f\n"+
+			"  kρ (тепловий опір ґрунту %.2f К·м/Вт) = %.3f\n"+
+			"  kn (кабелів поруч: %d) = %.3f\n"+
+			"  kпр (спосіб прокладання) = %.3f\n"+
+			"  Загальний коефіцієнт k = %.3f\n",
+		conditions.Laying,
+		conditions.AmbientTemp, f.Temperature,
+		conditions.SoilResistivity, f.Soil,
+		conditions.CablesCount, f.Grouping,
+		f.Laying,
+		f.Total,
+	)
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+)
+
+// Відношення X0/X1 трансформатора зі схемою Y/Yн (трьохстрижневий магнітопровід,
+// потік нульової послідовності замикається через бак)
+const yynZeroSequenceRatio = 8.0
+
+// Дані нульової послідовності для розрахунку однофазного КЗ
+type EarthFaultParams struct {
+	Connection string  // Схема з'єднання обмоток трансформатора: Dyn, Yyn, YNyn, Yd, YNd
+	Rc0N       float64 // Опори нульової послідовності системи в нормальному режимі, Ом (сторона ВН)
+	Xc0N       float64
+	Rc0Min     float64 // Опори нульової послідовності системи в мінімальному режимі, Ом (сторона ВН)
+	Xc0Min     float64
+}
+
+// Струми однофазного КЗ на землю у вузлі мережі
+type NodeEarthFault struct {
+	Node  string
+	Z0Sum float64 // |Z0Σ| нормального режиму, Ом
+	Z0Min float64 // |Z0Σ| мінімального режиму, Ом
+	I1    float64 // I(1) нормального режиму, А
+	I1Min float64 // I(1) мінімального режиму, А
+}
+
+// Опори нульової послідовності на шинах НН (приведені), нормальний та мінімальний режими.
+// grounded = false, якщо нейтраль обмотки НН не заземлена і струм КЗ на землю не протікає
+func busZeroSequence(params EarthFaultParams, rt, xt, kPr float64) (z0, z0Min complex128, grounded bool, err error) {
+	zt0 := complex(rt*kPr, xt*kPr)
+	switch params.Connection {
+	case "Dyn":
+		// Трикутник ВН замикає струми нульової послідовності — система не впливає
+		return zt0, zt0, true, nil
+	case "Yyn":
+		// Нейтраль ВН ізольована — лише опір трансформатора з потоком через бак
+		zYyn := complex(rt*kPr, xt*kPr*yynZeroSequenceRatio)
+		return zYyn, zYyn, true, nil
+	case "YNyn":
+		// Обидві нейтралі заземлені — до трансформатора додається опір системи
+		zc := complex(params.Rc0N*kPr, params.Xc0N*kPr)
+		zcMin := complex(params.Rc0Min*kPr, params.Xc0Min*kPr)
+		return zt0 + zc, zt0 + zcMin, true, nil
+	case "Yd", "YNd":
+		return 0, 0, false, nil
+	}
+	return 0, 0, false, fmt.Errorf("невідома схема з'єднання обмоток: %s", params.Connection)
+}
+
+// Струм однофазного КЗ I(1) = √3·U / |2·Z1Σ + Z0Σ| (Z2 = Z1)
+func calculateI1(uNn float64, z1, z0 complex128) float64 {
+	return math.Sqrt(3.0) * uNn * 1000.0 / cmplx.Abs(2*z1+z0)
+}
+
+// Струми однофазного КЗ у кожному вузлі мережі
+func calculateEarthFaults(
+	faults []NodeFaultCurrents,
+	params EarthFaultParams,
+	rt, xt, kPr, uNn float64,
+) ([]NodeEarthFault, bool, error) {
+	busZ0, busZ0Min, grounded, err := busZeroSequence(params, rt, xt, kPr)
+	if err != nil || !grounded {
+		return nil, grounded, err
+	}
+
+	var result []NodeEarthFault
+	for _, f := range faults {
+		line0 := complex(f.RZero, f.XZero)
+		z0 := busZ0 + line0
+		z0Min := busZ0Min + line0
+		z1 := complex(f.RSum, f.XSum)
+		z1Min := complex(f.RSumMin, f.XSumMin)
+		result = append(result, NodeEarthFault{
+			Node:  f.Node,
+			Z0Sum: cmplx.Abs(z0),
+			Z0Min: cmplx.Abs(z0Min),
+			I1:    calculateI1(uNn, z1, z0),
+			I1Min: calculateI1(uNn, z1Min, z0Min),
+		})
+	}
+	return result, true, nil
+}
+
+// Таблиця струмів однофазного КЗ у вузлах мережі
+func describeEarthFaults(params EarthFaultParams, faults []NodeEarthFault, grounded bool) string {
+	if !grounded {
+		return fmt.Sprintf("Однофазне КЗ на землю (схема %s): нейтраль мережі НН не заземлена,\n"+
+			"струм замикання визначається ємністю мережі і тут не розраховується\n", params.Connection)
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Струми однофазного КЗ на землю (схема %s):\n", params.Connection)
+	sb.WriteString("Вузол  Z0Σ, Ом   Z0Σ.мін, Ом  I(1), А    I(1)min, А\n")
+	for _, f := range faults {
+		fmt.Fprintf(&sb, "%-6s %8.2f  %11.2f  %9.2f  %10.2f\n",
+			f.Node, f.Z0Sum, f.Z0Min, f.I1, f.I1Min)
+	}
+	return sb.String()
+}
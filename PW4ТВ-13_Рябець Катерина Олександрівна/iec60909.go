@@ -0,0 +1,104 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Коефіцієнт збільшення активного опору ліній для мінімального струму КЗ
+// (температура провідника наприкінці КЗ θe = 80 °C: 1 + 0,004·(80 − 20))
+const lineTemperatureFactor = 1 + 0.004*(80-20)
+
+// Стандартні номінальні напруги мереж, кВ
+var standardVoltages = []float64{0.4, 0.69, 3, 6, 10, 20, 35, 110, 150, 220, 330}
+
+// Коефіцієнти напруги c за IEC 60909
+type VoltageFactors struct {
+	CMax float64
+	CMin float64
+}
+
+// Струми КЗ у вузлі за IEC 60909 поряд зі спрощеним методом
+type IECNodeCurrents struct {
+	Node       string
+	IkMax      float64 // Ik'' max за IEC 60909, А
+	IkMin      float64 // Ik'' min за IEC 60909, А
+	ClassicMax float64 // I(3) нормального режиму спрощеним методом, А
+	ClassicMin float64 // I(3) мінімального режиму спрощеним методом, А
+}
+
+// Результат розрахунку за IEC 60909
+type IECResult struct {
+	Un    float64 // Номінальна напруга мережі НН, кВ
+	C     VoltageFactors
+	XtRel float64 // Відносний реактивний опір трансформатора xT
+	KT    float64 // Коригувальний коефіцієнт опору трансформатора
+	Nodes []IECNodeCurrents
+}
+
+// Номінальна напруга мережі за середньою напругою ступеня (Uср ≈ 1,05·Uном)
+func standardNominalVoltage(uAvg float64) float64 {
+	best := standardVoltages[0]
+	for _, u := range standardVoltages {
+		if math.Abs(uAvg/1.05-u) < math.Abs(uAvg/1.05-best) {
+			best = u
+		}
+	}
+	return best
+}
+
+// Коефіцієнти напруги c: для мереж до 1 кВ (допуск +10 %) та понад 1 кВ
+func iecVoltageFactors(un float64) VoltageFactors {
+	if un <= 1.0 {
+		return VoltageFactors{CMax: 1.10, CMin: 0.95}
+	}
+	return VoltageFactors{CMax: 1.10, CMin: 1.00}
+}
+
+// Коригувальний коефіцієнт опору двообмоткового трансформатора KT = 0,95·cmax / (1 + 0,6·xT)
+func transformerCorrectionKT(cMax, xtRel float64) float64 {
+	return 0.95 * cMax / (1 + 0.6*xtRel)
+}
+
+// Струми КЗ у вузлах мережі за IEC 60909. Опори системи беруться без змін,
+// опори трансформатора множаться на KT, активний опір ліній
+// у мінімальному режимі — на lineTemperatureFactor
+func calculateIEC60909(
+	faults []NodeFaultCurrents,
+	xtRel, uNn, rt, xt, kPr float64,
+	rcN, xcN, rcMin, xcMin float64,
+) IECResult {
+	res := IECResult{Un: standardNominalVoltage(uNn), XtRel: xtRel}
+	res.C = iecVoltageFactors(res.Un)
+	res.KT = transformerCorrectionKT(res.C.CMax, res.XtRel)
+
+	rtK := res.KT * rt * kPr
+	xtK := res.KT * xt * kPr
+	for _, f := range faults {
+		zMax := math.Hypot(rcN*kPr+rtK+f.R, xcN*kPr+xtK+f.X)
+		zMin := math.Hypot(rcMin*kPr+rtK+lineTemperatureFactor*f.R, xcMin*kPr+xtK+f.X)
+		res.Nodes = append(res.Nodes, IECNodeCurrents{
+			Node:       f.Node,
+			IkMax:      res.C.CMax * res.Un * 1000.0 / (math.Sqrt(3.0) * zMax),
+			IkMin:      res.C.CMin * res.Un * 1000.0 / (math.Sqrt(3.0) * zMin),
+			ClassicMax: f.Currents.First,
+			ClassicMin: f.Currents.Third,
+		})
+	}
+	return res
+}
+
+// Порівняльна таблиця струмів КЗ: спрощений метод та IEC 60909
+func describeIEC60909(res IECResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Розрахунок за IEC 60909 (Uном = %g кВ):\n", res.Un)
+	fmt.Fprintf(&sb, "  cmax = %.2f, cmin = %.2f\n", res.C.CMax, res.C.CMin)
+	fmt.Fprintf(&sb, "  xT = %.4f, KT = %.4f\n", res.XtRel, res.KT)
+	sb.WriteString("Вузол  I(3), А    Ik''max, А  I(3)min, А  Ik''min, А\n")
+	for _, n := range res.Nodes {
+		fmt.Fprintf(&sb, "%-6s %9.2f  %10.2f  %10.2f  %10.2f\n",
+			n.Node, n.ClassicMax, n.IkMax, n.ClassicMin, n.IkMin)
+	}
+	return sb.String()
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+)
+
+// Точність та граничне число ітерацій розрахунку усталеного режиму
+const (
+	loadFlowTolerance     = 1e-6 // Допустима зміна напруги між ітераціями, кВ
+	loadFlowMaxIterations = 100
+)
+
+// Навантаження вузла радіальної мережі
+type NodeLoad struct {
+	Node string  `json:"node"`
+	P    float64 `json:"p"` // Активна потужність, кВт
+	Q    float64 `json:"q"` // Реактивна потужність, квар
+}
+
+// Напруга вузла в усталеному режимі
+type NodeVoltage struct {
+	Node      string
+	U         float64 // Міжфазна напруга, кВ
+	Angle     float64 // Кут напруги, °
+	Deviation float64 // Відхилення від номінальної напруги, %
+}
+
+// Струм і втрати потужності на ділянці
+type BranchFlow struct {
+	From    string
+	To      string
+	I       float64 // Струм, А
+	LossesP float64 // Втрати активної потужності, кВт
+	LossesQ float64 // Втрати реактивної потужності, квар
+}
+
+// Результат розрахунку усталеного режиму
+type LoadFlowResult struct {
+	USupply    float64 // Напруга шин живлення, кВ
+	UNom       float64 // Номінальна напруга мережі, кВ
+	Iterations int
+	Voltages   []NodeVoltage
+	Branches   []BranchFlow
+	PSupply    float64 // Потужність, що надходить з шин живлення, кВт
+	QSupply    float64 // квар
+	LossesP    float64 // Сумарні втрати, кВт
+	LossesQ    float64 // квар
+}
+
+// Навантаження за замовчуванням: вузли мережі за замовчуванням (Iроб головної ділянки ≈ 150 А)
+const defaultLoadsJSON = `[
+  {"node": "2", "p": 300, "q": 150},
+  {"node": "3", "p": 400, "q": 200},
+  {"node": "5", "p": 500, "q": 250},
+  {"node": "6", "p": 300, "q": 150},
+  {"node": "7", "p": 250, "q": 120},
+  {"node": "8", "p": 200, "q": 100},
+  {"node": "9", "p": 150, "q": 80},
+  {"node": "10", "p": 300, "q": 150}
+]`
+
+// Зчитування навантажень вузлів з JSON
+func parseNodeLoads(text string) ([]NodeLoad, error) {
+	var loads []NodeLoad
+	if err := json.Unmarshal([]byte(text), &loads); err != nil {
+		return nil, fmt.Errorf("некоректний JSON навантажень: %v", err)
+	}
+	for i, l := range loads {
+		if l.Node == "" {
+			return nil, fmt.Errorf("навантаження %d: не задано вузол", i+1)
+		}
+	}
+	return loads, nil
+}
+
+// Розрахунок усталеного режиму радіальної мережі методом зворотного/прямого ходу:
+// зворотний хід — струми ділянок від кінцевих вузлів до шин живлення,
+// прямий хід — напруги вузлів від шин живлення за спадами напруги на ділянках
+func calculateLoadFlow(network RadialNetwork, loads []NodeLoad, uSupply float64) (LoadFlowResult, error) {
+	res := LoadFlowResult{USupply: uSupply, UNom: standardNominalVoltage(uSupply)}
+	nodes := network.pathImpedances()
+	index := map[string]int{}
+	for i, n := range nodes {
+		index[n.Node] = i
+	}
+
+	load := make([]complex128, len(nodes)) // кВА
+	for _, l := range loads {
+		i, ok := index[l.Node]
+		if !ok {
+			return res, fmt.Errorf("навантаження приєднано до вузла %s, якого немає в мережі", l.Node)
+		}
+		load[i] += complex(l.P, l.Q)
+	}
+
+	// Опір ділянки, що живить вузол
+	z := make([]complex128, len(nodes))
+	for _, b := range network.Branches {
+		z[index[b.To]] = complex(b.Length*b.R0, b.Length*b.X0)
+	}
+
+	v := make([]complex128, len(nodes))
+	for i := range v {
+		v[i] = complex(uSupply, 0)
+	}
+	current := make([]complex128, len(nodes)) // Струм ділянки, що живить вузол, А
+	sqrt3 := complex(math.Sqrt(3.0), 0)
+
+	converged := false
+	for res.Iterations < loadFlowMaxIterations && !converged {
+		res.Iterations++
+
+		// Зворотний хід: вузли в порядку, зворотному до обходу в глибину
+		for i := len(nodes) - 1; i >= 0; i-- {
+			current[i] = cmplx.Conj(load[i] / (sqrt3 * v[i]))
+		}
+		for i := len(nodes) - 1; i > 0; i-- {
+			current[index[nodes[i].Parent]] += current[i]
+		}
+
+		// Прямий хід: ΔU = √3·Z·I
+		converged = true
+		for i := 1; i < len(nodes); i++ {
+			u := v[index[nodes[i].Parent]] - sqrt3*z[i]*current[i]/1000.0
+			// Напруга, що стала нескінченною або невизначеною, означає розбіжність ітерацій
+			// (навантаження перевищує пропускну здатність мережі)
+			if cmplx.IsNaN(u) || cmplx.IsInf(u) {
+				return res, fmt.Errorf("розрахунок розійшовся на ітерації %d: напруга у вузлі %s невизначена, навантаження завелике для мережі",
+					res.Iterations, nodes[i].Node)
+			}
+			if cmplx.Abs(u-v[i]) > loadFlowTolerance {
+				converged = false
+			}
+			v[i] = u
+		}
+	}
+	if !converged {
+		return res, fmt.Errorf("розрахунок не зійшовся за %d ітерацій", loadFlowMaxIterations)
+	}
+
+	for i, n := range nodes {
+		res.Voltages = append(res.Voltages, NodeVoltage{
+			Node:      n.Node,
+			U:         cmplx.Abs(v[i]),
+			Angle:     cmplx.Phase(v[i]) * 180 / math.Pi,
+			Deviation: (cmplx.Abs(v[i]) - res.UNom) / res.UNom * 100,
+		})
+		if i == 0 {
+			s := sqrt3 * v[0] * cmplx.Conj(current[0])
+			res.PSupply, res.QSupply = real(s), imag(s)
+			continue
+		}
+		i2 := cmplx.Abs(current[i]) * cmplx.Abs(current[i])
+		flow := BranchFlow{
+			From:    n.Parent,
+			To:      n.Node,
+			I:       cmplx.Abs(current[i]),
+			LossesP: 3 * i2 * real(z[i]) / 1000.0,
+			LossesQ: 3 * i2 * imag(z[i]) / 1000.0,
+		}
+		res.LossesP += flow.LossesP
+		res.LossesQ += flow.LossesQ
+		res.Branches = append(res.Branches, flow)
+	}
+	return res, nil
+}
+
+// Звіт з напругами вузлів, струмами та втратами на ділянках
+func describeLoadFlow(res LoadFlowResult) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Усталений режим мережі (Uшин = %.2f кВ, Uном = %g кВ, ітерацій: %d):\n",
+		res.USupply, res.UNom, res.Iterations)
+	sb.WriteString("Вузол  U, кВ     δ, °     δU, %\n")
+	minNode := res.Voltages[0]
+	for _, v := range res.Voltages {
+		fmt.Fprintf(&sb, "%-6s %7.3f  %7.3f  %7.2f\n", v.Node, v.U, v.Angle, v.Deviation)
+		if v.U < minNode.U {
+			minNode = v
+		}
+	}
+	sb.WriteString("Ділянка  I, А      ΔP, кВт   ΔQ, квар\n")
+	for _, b := range res.Branches {
+		fmt.Fprintf(&sb, "%-8s %8.2f  %8.3f  %9.3f\n", b.From+"-"+b.To, b.I, b.LossesP, b.LossesQ)
+	}
+	fmt.Fprintf(&sb, "Потужність з шин живлення: P = %.2f кВт, Q = %.2f квар\n", res.PSupply, res.QSupply)
+	lossesPct := 0.0
+	if res.PSupply > 0 {
+		lossesPct = res.LossesP / res.PSupply * 100
+	}
+	fmt.Fprintf(&sb, "Сумарні втрати: ΔP = %.3f кВт (%.2f %%), ΔQ = %.3f квар\n",
+		res.LossesP, lossesPct, res.LossesQ)
+	fmt.Fprintf(&sb, "Найнижча напруга: вузол %s, U = %.3f кВ (%.2f %%)\n", minNode.Node, minNode.U, minNode.Deviation)
+	return sb.String()
+}
@@ -9,15 +9,16 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"strings"
 )
 
+// Переріз кабелю та допустимі струми за класами напруги.
+// Ключ — клас напруги в кВ ("3" означає до 3 кВ включно, зокрема 0,4 кВ)
 type Cable struct {
-	Sech    int `json:"sech"`
-	UpTo3kV int `json:"up_to_3_kV"`
-	SixKV   int `json:"6_kV"`
-	TenKV   int `json:"10_kV"`
+	Sech     int            `json:"sech"`
+	Ampacity map[string]int `json:"ampacity"`
 }
 
 type CableData struct {
@@ -93,34 +94,107 @@ func loadEconomicDensityData(filename string) ([]EconomicDensityData, error) {
 
 // Завдання 1-2
 
-func findSuitableCable(
+// Кабель-кандидат, що проходить за допустимим струмом
+type cableCandidate struct {
+	Data     *CableData // Тип кабелю (провідник, ізоляція, оболонка)
+	Cable    Cable      // Переріз
+	Ampacity int        // Допустимий струм при заданій напрузі (табличний), А
+	Derated  float64    // Допустимий струм з урахуванням умов прокладання, А
+}
+
+// Усі класи напруги, наявні в таблицях кабелів, за зростанням
+func voltageClasses(cablesData []CableData) []float64 {
+	seen := map[float64]bool{}
+	var classes []float64
+	for _, cd := range cablesData {
+		for _, c := range cd.Cables {
+			for key := range c.Ampacity {
+				v, err := strconv.ParseFloat(key, 64)
+				if err == nil && !seen[v] {
+					seen[v] = true
+					classes = append(classes, v)
+				}
+			}
+		}
+	}
+	sort.Float64s(classes)
+	return classes
+}
+
+// Найближчий застосовний клас напруги: найменший клас, не нижчий за введену напругу
+func nearestVoltageClass(voltage float64, cablesData []CableData) (float64, bool) {
+	for _, class := range voltageClasses(cablesData) {
+		if class >= voltage {
+			return class, true
+		}
+	}
+	return 0, false
+}
+
+// Допустимий струм перерізу для класу напруги (0 — немає даних)
+func cableAmpacity(c Cable, voltageClass float64) int {
+	return c.Ampacity[strconv.FormatFloat(voltageClass, 'f', -1, 64)]
+}
+
+// Порядок матеріалів при однаковому перерізі: спершу дешевший алюміній
+func materialRank(conductor string) int {
+	if strings.EqualFold(conductor, "aluminium") {
+		return 0
+	}
+	return 1
+}
+
+// Усі перерізи всіх типів кабелів, допустимий струм яких з урахуванням
+// поправкового коефіцієнта не менший за impa, упорядковані за перерізом, а потім за матеріалом
+func findSuitableCables(
 	impa int,
 	voltage float64,
+	correction float64,
 	cablesData []CableData,
-) *CableData {
-	for _, cd := range cablesData {
+) []cableCandidate {
+	var candidates []cableCandidate
+	for i := range cablesData {
+		cd := &cablesData[i]
+		// Найменший переріз цього типу, що проходить за струмом
+		var best *cableCandidate
 		for _, c := range cd.Cables {
-			//Вибір значення струму з полів залежно від напруги
-			var current int
-
-			switch int(voltage) {
-			case 3:
-				current = c.UpTo3kV
-			case 6:
-				current = c.SixKV
-			case 10:
-				current = c.TenKV
-			default:
-				current = 0
+			current := cableAmpacity(c, voltage)
+			derated := float64(current) * correction
+			if current == 0 || derated < float64(impa) {
+				continue
 			}
-
-			// Якщо кабель має ненульову пропускну здатність і вона дорівнює impa
-			if current != 0 && current == impa {
-				return &cd
+			if best == nil || c.Sech < best.Cable.Sech {
+				best = &cableCandidate{Data: cd, Cable: c, Ampacity: current, Derated: derated}
 			}
 		}
+		if best != nil {
+			candidates = append(candidates, *best)
+		}
 	}
-	return nil
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Cable.Sech != candidates[j].Cable.Sech {
+			return candidates[i].Cable.Sech < candidates[j].Cable.Sech
+		}
+		return materialRank(candidates[i].Data.Conductor) < materialRank(candidates[j].Data.Conductor)
+	})
+	return candidates
+}
+
+// Обґрунтування вибору кабелю за допустимим струмом
+func describeCableChoice(impa int, candidates []cableCandidate) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Вибір за допустимим струмом (k·Iдоп >= Iм.па = %d А):\n", impa)
+	for i, c := range candidates {
+		mark := " "
+		if i == 0 {
+			mark = "*"
+		}
+		fmt.Fprintf(&sb, "%s %s/%s/%s: %d мм², Iдоп = %d А, k·Iдоп = %.1f А\n",
+			mark, c.Data.Conductor, c.Data.Insulation, c.Data.Sheath, c.Cable.Sech, c.Ampacity, c.Derated)
+	}
+	sb.WriteString("Обрано найменший переріз (*); при однаковому перерізі перевага алюмінію\n")
+	return sb.String()
 }
 
 func findEconomicCurrentDensity(
@@ -153,6 +227,7 @@ func findClosestSech(
 	cableData *CableData,
 	thermalStability float64,
 	currentVoltage float64,
+	classes []float64,
 ) (*Cable, float64) {
 	if cableData == nil {
 		return nil, 0
@@ -172,13 +247,14 @@ func findClosestSech(
 			}
 		}
 	}
-	// Якщо знайдено кабель із більшим перерізом, зменшити напругу (у прикладі: 10->6->3)
+	// Якщо знайдено кабель із більшим перерізом, перейти на нижчий клас напруги (у прикладі: 10->6->3)
 	if closest != nil {
 		newVoltage := currentVoltage
-		if currentVoltage == 10.0 {
-			newVoltage = 6.0
-		} else if currentVoltage == 6.0 {
-			newVoltage = 3.0
+		for i := len(classes) - 1; i >= 0; i-- {
+			if classes[i] < currentVoltage {
+				newVoltage = classes[i]
+				break
+			}
 		}
 		return closest, newVoltage
 	}
@@ -206,29 +282,55 @@ func getThermalCoefficient(insulation string) float64 {
 }
 
 func calculateResultsWithDensity(
-	currentIk float64,
 	timeTf float64,
+	timeTau float64,
 	powerSm float64,
 	voltage float64,
 	timeTm float64,
 	powerKZ float64,
+	transformer TransformerData,
+	devices []SwitchgearData,
+	conditions InstallationConditions,
+	line LineParams,
 	cableData []CableData,
 	economicDensityData []EconomicDensityData,
+	correctionData CorrectionFactorsData,
+	impedanceData []ConductorImpedanceData,
 ) string {
 	// Струм нормального режиму
 	im := (powerSm / 2.0) / (math.Sqrt(3.0) * voltage)
 	// Струм після аварії
 	impa := int(2 * im)
 
-	// Пошук кабелю за Iм.па
-	suitableCable := findSuitableCable(impa, voltage, cableData)
+	// Клас напруги кабелю для введеної напруги мережі
+	voltageClass, ok := nearestVoltageClass(voltage, cableData)
+	if !ok {
+		return fmt.Sprintf(
+			"Струм після аварії (Iм.па): %d А\nУ таблицях немає кабелів на напругу %.2f кВ.",
+			impa, voltage,
+		)
+	}
 
-	if suitableCable == nil {
+	// Поправкові коефіцієнти на умови прокладання
+	factors, err := calculateCorrectionFactors(conditions, correctionData)
+	if err != nil {
+		return fmt.Sprintf("Струм після аварії (Iм.па): %d А\n%v", impa, err)
+	}
+	factorsText := describeCorrectionFactors(conditions, factors)
+
+	// Пошук кабелю за Iм.па
+	candidates := findSuitableCables(impa, voltageClass, factors.Total, cableData)
+	if len(candidates) == 0 {
 		return fmt.Sprintf(
-			"Струм після аварії (Iм.па): %d А\nПідходящий кабель не знайдено.",
-			impa,
+			"Струм після аварії (Iм.па): %d А\n%sПідходящий кабель не знайдено: "+
+				"жоден переріз не має допустимого струму >= Iм.па для класу %g кВ.",
+			impa, factorsText, voltageClass,
 		)
 	}
+	choice := candidates[0]
+	suitableCable := choice.Data
+	cableChoice := fmt.Sprintf("Клас напруги кабелю: %g кВ (для мережі %g кВ)\n", voltageClass, voltage) +
+		factorsText + describeCableChoice(impa, candidates)
 	// Економічна густина
 	economicDensity := findEconomicCurrentDensity(
 		suitableCable.Conductor,
@@ -236,9 +338,28 @@ func calculateResultsWithDensity(
 		timeTm,
 		economicDensityData,
 	)
-	// Термічна стійкість
+	// Опори системи та трансформатора, приведені до напруги обмотки НН
+	uSn := transformer.ULV
+	ukPercent := transformer.Uk
+	sNomT := transformer.SNom
+	xc := (uSn * uSn) / powerKZ
+	rt, xt := transformer.impedance(uSn)
+	xSum := xc + xt
+	zSum := math.Sqrt(rt*rt + xSum*xSum)
+
+	// Струм КЗ
+	ip0 := uSn / (math.Sqrt(3.0) * zSum)
+
+	// Відношення X/R кола КЗ за опорами трансформатора та системи
+	xrRatio := 0.0
+	if rt > 0 {
+		xrRatio = xSum / rt
+	}
+
+	// Термічна стійкість за тепловим імпульсом розрахованого струму КЗ
 	thermalCoefficient := getThermalCoefficient(suitableCable.Insulation)
-	thermalStability := currentIk * math.Sqrt(timeTf) / thermalCoefficient
+	dynamics := calculateShortCircuitDynamics(ip0, xrRatio, timeTau, timeTf, thermalCoefficient)
+	thermalStability := dynamics.SechMin
 
 	if economicDensity == nil {
 		// Якщо не знайшли економічної густини
@@ -247,9 +368,11 @@ func calculateResultsWithDensity(
 				"Струм після аварії (Iм.па): %d А\n"+
 				"Термічна стійкість (s): %.2f мм²\n"+
 				"Підходящий кабель: провідник - %s, ізоляція - %s, оболонка - %s\n"+
+				"%s"+
 				"Не вдалося знайти економічну густину струму.",
 			im, impa, thermalStability,
 			suitableCable.Conductor, suitableCable.Insulation, suitableCable.Sheath,
+			cableChoice,
 		)
 	}
 
@@ -257,37 +380,33 @@ func calculateResultsWithDensity(
 	sek := im / *economicDensity
 
 	// Шукаємо кабель з урахуванням термічної стійкості
-	closestCable, foundVoltage := findClosestSech(suitableCable, thermalStability, voltage)
+	closestCable, foundVoltage := findClosestSech(suitableCable, thermalStability, voltageClass, voltageClasses(cableData))
 	if closestCable == nil {
 		return fmt.Sprintf(
 			"Неможливо знайти підходящу секцію для значення термічної стійкості %.2f мм².",
 			thermalStability,
 		)
 	}
-
-	var uSn float64
-	var ukPercent float64
-	var sNomT float64
-	if voltage == 10.0 {
-		uSn = 10.5
-		ukPercent = 10.5
-	} else {
-		uSn = 6.3
-		ukPercent = 6.3
+	// Переріз не може бути меншим за обраний за допустимим струмом
+	if closestCable.Sech < choice.Cable.Sech {
+		closestCable = &choice.Cable
+		foundVoltage = voltageClass
 	}
 
-	if foundVoltage == 10.0 {
-		sNomT = 10.5
-	} else {
-		sNomT = 6.3
+	// Перевірка втрати напруги в нормальному режимі та збільшення перерізу за потреби
+	dropResult, err := checkVoltageDrop(suitableCable, closestCable.Sech, im, voltage, line, impedanceData)
+	if err != nil {
+		return fmt.Sprintf("Переріз жил кабеля: %d мм²\n%v", closestCable.Sech, err)
+	}
+	dropText := describeVoltageDrop(closestCable.Sech, line, dropResult)
+	if dropResult.Upsized {
+		for _, c := range suitableCable.Cables {
+			if c.Sech == dropResult.Sech {
+				upsized := c
+				closestCable = &upsized
+			}
+		}
 	}
-
-	xc := (uSn * uSn) / powerKZ
-	xt := (ukPercent / 100.0) * (uSn * uSn) / sNomT
-	xSum := xc + xt
-
-	// Струм КЗ
-	ip0 := uSn / (math.Sqrt(3.0) * xSum)
 
 	// Формування підсумкового тексту
 	return fmt.Sprintf(
@@ -295,34 +414,48 @@ func calculateResultsWithDensity(
 			"Струм після аварії (Iм.па): %d А\n"+
 			"Термічна стійкість (s): %.2f мм²\n"+
 			"Підходящий кабель: провідник - %s, ізоляція - %s, оболонка - %s\n"+
+			"%s"+
 			"Економічна густина струму: %.2f A/мм²\n"+
 			"Економічний переріз: %.2f мм²\n"+
 			"Переріз жил кабеля: %d мм², Номінальна напруга: %.1f кВ\n"+
+			"%s"+
 			"Перевірка:\n"+
+			"%s"+
 			"U_с.н. = %.2f кВ\n"+
 			"U_к%% = %.2f %%\n"+
 			"S_ном.т = %.2f МВА\n"+
 			"Xc = %.4f Ом\n"+
 			"Xt = %.4f Ом\n"+
+			"Rt = %.4f Ом\n"+
 			"Сумарний опір XΣ = %.4f Ом\n"+
-			"Початкове значення струму трифазного КЗ Iп0 = %.4f кА",
+			"Повний опір ZΣ = %.4f Ом\n"+
+			"Початкове значення струму трифазного КЗ Iп0 = %.4f кА\n"+
+			"%s"+
+			"%s",
 		im,
 		impa,
 		thermalStability,
 		suitableCable.Conductor,
 		suitableCable.Insulation,
 		suitableCable.Sheath,
+		cableChoice,
 		*economicDensity,
 		sek,
 		closestCable.Sech,
 		foundVoltage,
+		dropText,
+		describeTransformer(transformer),
 		uSn,
 		ukPercent,
 		sNomT,
 		xc,
 		xt,
+		rt,
 		xSum,
+		zSum,
 		ip0,
+		describeShortCircuitDynamics(dynamics),
+		describeSwitchgearChecks(devices, voltage, float64(impa), dynamics),
 	)
 }
 
@@ -420,31 +553,40 @@ func calculateZsumN(
 	}
 }
 
+// Результати основного розрахунку, на які спираються додаткові розділи звіту
+type mainCircuit struct {
+	Transformer TransformerData
+	UVn, UNn    float64 // Напруги ВН та НН, кВ
+	Rt, Xt      float64 // Опори трансформатора, приведені до ВН, Ом
+	KPr         float64 // Коефіцієнт приведення
+	RcN, XcN    float64 // Опори системи в нормальному режимі, Ом
+	RcMin       float64 // Опори системи в мінімальному режимі, Ом
+	XcMin       float64
+	MaxTap      TapFaultCase // Положення РПН з найбільшим струмом КЗ (нормальний режим)
+	MinTap      TapFaultCase // Положення РПН з найменшим струмом КЗ (мінімальний режим)
+	Network     RadialNetwork
+	Faults      []NodeFaultCurrents // Струми КЗ у вузлах мережі з урахуванням РПН
+}
+
+// Основний розрахунок: струми КЗ на шинах ВН і НН, положення РПН та вузли радіальної мережі
 func calculateMain(
-	uKmax float64,
-	uVn float64,
-	uNn float64,
-	sNomT float64,
+	transformer TransformerData,
 	rcN float64,
 	xcN float64,
 	rcMin float64,
 	xcMin float64,
-	r0 float64,
-	x0 float64,
-	section1_2 float64,
-	section2_3 float64,
-	section4_5 float64,
-	section5_6 float64,
-	section6_7 float64,
-	section7_8 float64,
-	section8_9 float64,
-	section9_10 float64,
-) string {
-	// 1. Xт
-	xt := calculateXtValue(uKmax, uVn, sNomT)
+	network RadialNetwork,
+) (string, mainCircuit) {
+	uVn := transformer.UHV
+	uNn := transformer.ULV
+
+	// 1. Rт та Xт (приведені до напруги ВН)
+	rt, xt := transformer.impedance(uVn)
+	rSh := rcN + rt
+	rShMin := rcMin + rt
 
 	// 2. Zш та Zш.min
-	zshVals := calculateZshValues(rcN, xcN, rcMin, xcMin, xt)
+	zshVals := calculateZshValues(rSh, xcN, rShMin, xcMin, xt)
 	xSh := zshVals.First
 	zSh := zshVals.Second
 	xShMin := zshVals.Third
@@ -461,7 +603,7 @@ func calculateMain(
 	kPr := calculateK(uVn, uNn)
 
 	// 5. Опори на шинах 10 кВ (номінальний та мінімальний)
-	zshNVals := calculateZshNValues(rcN, xSh, rcMin, xShMin, kPr)
+	zshNVals := calculateZshNValues(rSh, xSh, rShMin, xShMin, kPr)
 	rShN := zshNVals.First
 	xShN := zshNVals.Second
 	zShN := zshNVals.Third
@@ -476,31 +618,39 @@ func calculateMain(
 	i3ShNMin := iValsN.Third
 	i2ShNMin := iValsN.Fourth
 
-	// 7. Сумарна довжина
-	totalLength := section1_2 + section2_3 + section4_5 + section5_6 + section6_7 + section7_8 + section8_9 + section9_10
-
-	// Rл, Xл
-	Rl := totalLength * r0
-	Xl := totalLength * x0
-
-	// 8. Опори в точці 10 (ZΣ.н, мін. тощо)
-	zSumVals := calculateZsumN(Rl, Xl, rShN, xShN, rShNMin, xShNMin)
-	rSumN := zSumVals.First
-	xSumN := zSumVals.Second
-	zSumN := zSumVals.Third
-	rSumNMin := zSumVals.Fourth
-	xSumNMin := zSumVals.Fifth
-	zSumNMin := zSumVals.Sixth
-
-	// 9. Струми КЗ в точці 10 (номінальний/мінімальний)
-	iValsL := calculateI(uNn, zSumN, zSumNMin)
-	i3LN := iValsL.First
-	i2LN := iValsL.Second
-	i3LNMin := iValsL.Third
-	i2LNMin := iValsL.Fourth
+	// 6а. Струми КЗ на шинах НН у крайніх положеннях РПН
+	tapCases := calculateTapCases(transformer, rcN, xcN, rcMin, xcMin)
+	maxCase, minCase := worstTapCases(tapCases)
+
+	// 7-9. Опори та струми КЗ у кожному вузлі радіальної мережі
+	// (нормальний режим — положення РПН з найбільшим струмом, мінімальний — з найменшим)
+	networkFaults := calculateNetworkFaults(
+		network, uNn,
+		maxCase.ZN.First, maxCase.ZN.Second,
+		minCase.ZN.Fourth, minCase.ZN.Fifth,
+	)
+
+	circuit := mainCircuit{
+		Transformer: transformer,
+		UVn:         uVn,
+		UNn:         uNn,
+		Rt:          rt,
+		Xt:          xt,
+		KPr:         kPr,
+		RcN:         rcN,
+		XcN:         xcN,
+		RcMin:       rcMin,
+		XcMin:       xcMin,
+		MaxTap:      maxCase,
+		MinTap:      minCase,
+		Network:     network,
+		Faults:      networkFaults,
+	}
 
 	// Формуємо текстовий звіт
 	return fmt.Sprintf(`
+%s
+Активний опір трансформатора: RТ = %.2f Ом
 Реактивний опір трансформатора: XТ = %.2f Ом
 
 Xш = %.2f Ом
@@ -535,29 +685,10 @@ I(2)ш (норм.) = %.2f А
 I(3)ш.min = %.2f А
 I(2)ш.min = %.2f А
 
-Сумарна довжина: %.2f км
-Rл = %.2f Ом
-Xл = %.2f Ом
-
-Нормальний режим (точка 10):
-RΣ.н = %.2f Ом
-XΣ.н = %.2f Ом
-ZΣ.н = %.2f Ом
-
-Мінімальний режим (точка 10):
-RΣ.н.мін = %.2f Ом
-XΣ.н.мін = %.2f Ом
-ZΣ.н.мін = %.2f Ом
-
-Струми КЗ у точці 10 (нормальний режим):
-I(3)ш = %.2f А
-I(2)ш = %.2f А
-
-Струми КЗ у точці 10 (мінімальний режим):
-I(3)ш.min = %.2f А
-I(2)ш.min = %.2f А
-`,
-		xt,
+%s
+%s`,
+		describeTransformer(transformer),
+		rt, xt,
 		xSh, zSh, xShMin, zShMin,
 		i3Sh, i2Sh,
 		i3ShMin, i2ShMin,
@@ -565,75 +696,222 @@ I(2)ш.min = %.2f А
 		rShN, xShN, zShN,
 		rShNMin, xShNMin, zShNMin,
 		i3ShN, i2ShN, i3ShNMin, i2ShNMin,
-		totalLength, Rl, Xl,
-		rSumN, xSumN, zSumN,
-		rSumNMin, xSumNMin, zSumNMin,
-		i3LN, i2LN,
-		i3LNMin, i2LNMin,
+		describeTapCases(tapCases, maxCase, minCase),
+		describeNetworkFaults(networkFaults),
+	), circuit
+}
+
+// 10. Струми однофазного КЗ на землю у вузлах мережі
+func reportEarthFaults(c mainCircuit, earth EarthFaultParams) string {
+	earthFaults, grounded, err := calculateEarthFaults(c.Faults, earth, c.Rt, c.Xt, c.KPr, c.UNn)
+	if err != nil {
+		return "Однофазне КЗ: " + err.Error() + "\n"
+	}
+	return describeEarthFaults(earth, earthFaults, grounded)
+}
+
+// 10а. Підживлення КЗ від двигунів у вузлах мережі
+func reportMotors(c mainCircuit, motorsJSON string) string {
+	motors, err := parseMotorGroups(motorsJSON)
+	if err != nil {
+		return "Двигуни: " + err.Error() + "\n"
+	}
+	contributions, err := calculateMotorContributions(motors, c.Faults, c.UNn)
+	if err != nil {
+		return "Двигуни: " + err.Error() + "\n"
+	}
+	return describeMotorContributions(contributions, c.UNn)
+}
+
+// 11. Уставки релейного захисту, чутливість та селективність
+func reportProtection(c mainCircuit, protectionJSON string, settings ProtectionSettings) string {
+	protection, err := parseProtectionDevices(protectionJSON)
+	if err != nil {
+		return "Релейний захист: " + err.Error() + "\n"
+	}
+	protectionResults, err := calculateProtection(protection, c.Faults, settings, allSwitchgear)
+	if err != nil {
+		return "Релейний захист: " + err.Error() + "\n"
+	}
+	return describeProtection(protectionResults, settings)
+}
+
+// 12. Розрахунок у відносних одиницях (номінальне положення РПН)
+// з перевіркою за розрахунком в іменованих одиницях
+func reportPerUnit(c mainCircuit, sBase float64) string {
+	if !(sBase > 0) {
+		return "Розрахунок у відносних одиницях: базисна потужність має бути додатною\n"
+	}
+	perUnit := newPerUnitSystem(sBase, c.UVn, c.Transformer)
+	puElements, puResults := calculatePerUnit(
+		perUnit, c.RcN, c.XcN, c.RcMin, c.XcMin, c.Transformer, c.MaxTap, c.MinTap, c.Network,
 	)
+	return describePerUnit(perUnit, c.MaxTap, c.MinTap, puElements, puResults, c.Faults)
+}
+
+// 13. Струми КЗ у замкненій мережі за матрицею вузлових опорів
+func reportMesh(meshJSON, meshFault string, sBase float64) string {
+	if !(sBase > 0) {
+		return "Замкнена мережа: базисна потужність має бути додатною\n"
+	}
+	mesh, err := parseMeshNetwork(meshJSON)
+	if err != nil {
+		return "Замкнена мережа: " + err.Error() + "\n"
+	}
+	meshResults, err := calculateMeshFaults(mesh, sBase)
+	if err != nil {
+		return "Замкнена мережа: " + err.Error() + "\n"
+	}
+	return describeMeshFaults(mesh, meshResults, meshFault, sBase)
+}
+
+// 14. Усталений режим радіальної мережі (напруги, струми, втрати)
+func reportLoadFlow(network RadialNetwork, loadsJSON string, uSupply float64) string {
+	if !(uSupply > 0) {
+		return "Усталений режим: напруга шин живлення має бути додатною\n"
+	}
+	loads, err := parseNodeLoads(loadsJSON)
+	if err != nil {
+		return "Усталений режим: " + err.Error() + "\n"
+	}
+	loadFlow, err := calculateLoadFlow(network, loads, uSupply)
+	if err != nil {
+		return "Усталений режим: " + err.Error() + "\n"
+	}
+	return describeLoadFlow(loadFlow)
+}
+
+// 15. Порівняння з розрахунком за IEC 60909
+func reportIEC60909(c mainCircuit) string {
+	xtRel := c.Xt * c.Transformer.SNom / (c.UVn * c.UVn)
+	iec := calculateIEC60909(c.Faults, xtRel, c.UNn, c.Rt, c.Xt, c.KPr, c.RcN, c.XcN, c.RcMin, c.XcMin)
+	return describeIEC60909(iec)
 }
 
 type PageData struct {
-	CurrentIk string
-	TimeTf    string
-	PowerSm   string
-	Voltage   string
-	TimeTm    string
-	PowerKZ   string
-	Result12  string
-
-	UKmax       string
-	UVn         string
-	UNn         string
-	SNomT       string
-	RcN         string
-	XcN         string
-	RcMin       string
-	XcMin       string
-	R0          string
-	X0          string
-	Section1_2  string
-	Section2_3  string
-	Section4_5  string
-	Section5_6  string
-	Section6_7  string
-	Section7_8  string
-	Section8_9  string
-	Section9_10 string
-	Result3     string
+	TimeTf   string
+	TimeTau  string
+	PowerSm  string
+	Voltage  string
+	TimeTm   string
+	PowerKZ  string
+	Result12 string
+
+	Laying          string
+	AmbientTemp     string
+	SoilResistivity string
+	CablesCount     string
+	LineLength      string
+	PowerFactor     string
+	AllowedDrop     string
+
+	// Назви обраних трансформаторів та каталог для списків вибору
+	Transformer12 string
+	Transformer3  string
+	Transformers  []TransformerData
+
+	// Назви обраних апаратів та каталог для списків вибору
+	Breaker            string
+	Disconnector       string
+	CurrentTransformer string
+	Switchgear         []SwitchgearData
+
+	// uк та діапазон регулювання РПН; порожні поля — значення з каталогу
+	UkMin    string
+	UkNom    string
+	UkMax    string
+	TapRange string
+
+	RcN     string
+	XcN     string
+	RcMin   string
+	XcMin   string
+	Network string
+
+	Connection string
+	Rc0N       string
+	Xc0N       string
+	Rc0Min     string
+	Xc0Min     string
+	Method     string
+
+	Protection string
+	KRelInst   string
+	KRelTime   string
+	KSelfStart string
+	KReturn    string
+	TimeStep   string
+	TimeMin    string
+	Motors     string
+	SBase      string
+	Mesh       string
+	MeshFault  string
+	Loads      string
+	USupply    string
+
+	Result3 string
 }
 
 var tmpl *template.Template
 
-func handlerIndex(w http.ResponseWriter, r *http.Request) {
-	data := PageData{
-		// Дефолтні значення
-		CurrentIk: "2500",
-		TimeTf:    "2.5",
-		PowerSm:   "1300",
-		Voltage:   "10",
-		TimeTm:    "4000",
-		PowerKZ:   "2000",
-
-		UKmax:       "11.1",
-		UVn:         "115",
-		UNn:         "11",
-		SNomT:       "6.3",
-		RcN:         "10.65",
-		XcN:         "24.02",
-		RcMin:       "34.88",
-		XcMin:       "65.68",
-		R0:          "0.64",
-		X0:          "0.363",
-		Section1_2:  "0.2",
-		Section2_3:  "0.35",
-		Section4_5:  "0.2",
-		Section5_6:  "0.6",
-		Section6_7:  "2.0",
-		Section7_8:  "2.55",
-		Section8_9:  "3.37",
-		Section9_10: "3.1",
+// Значення полів форми за замовчуванням
+func defaultPageData() PageData {
+	return PageData{
+		TimeTf:  "2.5",
+		TimeTau: "0.06",
+		PowerSm: "1300",
+		Voltage: "10",
+		TimeTm:  "4000",
+		PowerKZ: "2000",
+
+		Laying:          "ground",
+		AmbientTemp:     "15",
+		SoilResistivity: "1.2",
+		CablesCount:     "1",
+		LineLength:      "1",
+		PowerFactor:     "0.9",
+		AllowedDrop:     "5",
+
+		Transformer12: "ТДН-10000/110",
+		Transformer3:  "ТМН-6300/110",
+		Transformers:  allTransformers,
+
+		Breaker:            "ВВ/TEL-10-20/1000",
+		Disconnector:       "РВ-10/630",
+		CurrentTransformer: "ТОЛ-10-100/5",
+		Switchgear:         allSwitchgear,
+
+		RcN:     "10.65",
+		XcN:     "24.02",
+		RcMin:   "34.88",
+		XcMin:   "65.68",
+		Network: defaultNetworkJSON,
+
+		Connection: "Dyn",
+		Rc0N:       "21.3",
+		Xc0N:       "48.04",
+		Rc0Min:     "69.76",
+		Xc0Min:     "131.36",
+		Method:     "classic",
+
+		Protection: defaultProtectionJSON,
+		KRelInst:   "1.2",
+		KRelTime:   "1.2",
+		KSelfStart: "1.5",
+		KReturn:    "0.95",
+		TimeStep:   "0.5",
+		TimeMin:    "0.5",
+		Motors:     defaultMotorsJSON,
+		SBase:      "100",
+		Mesh:       defaultMeshJSON,
+		MeshFault:  "1",
+		Loads:      defaultLoadsJSON,
+		USupply:    "10.5",
 	}
+}
+
+func handlerIndex(w http.ResponseWriter, r *http.Request) {
+	data := defaultPageData()
 	tmpl.Execute(w, data)
 }
 
@@ -641,56 +919,99 @@ func handlerIndex(w http.ResponseWriter, r *http.Request) {
 func handlerCalculate12(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodPost {
 		// Зчитуємо поля
-		currentIk := r.FormValue("currentIk")
 		timeTf := r.FormValue("timeTf")
+		timeTau := r.FormValue("timeTau")
 		powerSm := r.FormValue("powerSm")
 		voltage := r.FormValue("voltage")
 		timeTm := r.FormValue("timeTm")
 		powerKZ := r.FormValue("powerKZ")
+		laying := r.FormValue("laying")
+		ambientTemp := r.FormValue("ambientTemp")
+		soilResistivity := r.FormValue("soilResistivity")
+		cablesCount := r.FormValue("cablesCount")
+		lineLength := r.FormValue("lineLength")
+		powerFactor := r.FormValue("powerFactor")
+		allowedDrop := r.FormValue("allowedDrop")
+		transformerName := r.FormValue("transformer")
+		breakerName := r.FormValue("breaker")
+		disconnectorName := r.FormValue("disconnector")
+		ctName := r.FormValue("currentTransformer")
 
 		// Перетворюємо у float64
-		fIk, _ := strconv.ParseFloat(currentIk, 64)
 		fTf, _ := strconv.ParseFloat(timeTf, 64)
+		fTau, _ := strconv.ParseFloat(timeTau, 64)
 		fSm, _ := strconv.ParseFloat(powerSm, 64)
 		fVoltage, _ := strconv.ParseFloat(voltage, 64)
 		fTm, _ := strconv.ParseFloat(timeTm, 64)
 		fKZ, _ := strconv.ParseFloat(powerKZ, 64)
+		fAmbient, _ := strconv.ParseFloat(ambientTemp, 64)
+		fSoil, _ := strconv.ParseFloat(soilResistivity, 64)
+		nCables, _ := strconv.Atoi(cablesCount)
+		fLength, _ := strconv.ParseFloat(lineLength, 64)
+		fCosPhi, _ := strconv.ParseFloat(powerFactor, 64)
+		fAllowed, _ := strconv.ParseFloat(allowedDrop, 64)
+
+		conditions := InstallationConditions{
+			Laying:          laying,
+			AmbientTemp:     fAmbient,
+			SoilResistivity: fSoil,
+			CablesCount:     nCables,
+		}
 
-		result := calculateResultsWithDensity(
-			fIk, fTf, fSm, fVoltage, fTm, fKZ,
-			allCableData, allEconomicDensity,
-		)
+		// Лінія з двох паралельних кабелів (навантаження ділиться навпіл)
+		line := LineParams{
+			Length:      fLength,
+			PowerFactor: fCosPhi,
+			AllowedDrop: fAllowed,
+			Cables:      2,
+		}
 
-		// Формуємо дані для шаблону:
-		data := PageData{
-			CurrentIk: currentIk,
-			TimeTf:    timeTf,
-			PowerSm:   powerSm,
-			Voltage:   voltage,
-			TimeTm:    timeTm,
-			PowerKZ:   powerKZ,
-			Result12:  result,
-
-			// Поля Завдання 3 залишимо з дефолтами
-			UKmax:       "11.1",
-			UVn:         "115",
-			UNn:         "11",
-			SNomT:       "6.3",
-			RcN:         "10.65",
-			XcN:         "24.02",
-			RcMin:       "34.88",
-			XcMin:       "65.68",
-			R0:          "0.64",
-			X0:          "0.363",
-			Section1_2:  "0.2",
-			Section2_3:  "0.35",
-			Section4_5:  "0.2",
-			Section5_6:  "0.6",
-			Section6_7:  "2.0",
-			Section7_8:  "2.55",
-			Section8_9:  "3.37",
-			Section9_10: "3.1",
+		var result string
+		transformer := findTransformer(transformerName, allTransformers)
+		breaker := findSwitchgear(breakerName, "breaker", allSwitchgear)
+		disconnector := findSwitchgear(disconnectorName, "disconnector", allSwitchgear)
+		ct := findSwitchgear(ctName, "current_transformer", allSwitchgear)
+		if transformer == nil {
+			result = "Трансформатор не знайдено в каталозі: " + transformerName
+		} else if breaker == nil || disconnector == nil || ct == nil {
+			result = "Апарат не знайдено в каталозі"
+		} else if !transformer.matchesNetwork(fVoltage) {
+			result = fmt.Sprintf(
+				"Напруга обмотки НН трансформатора %s (%.1f кВ) не відповідає напрузі мережі %.1f кВ",
+				transformer.Name, transformer.ULV, fVoltage,
+			)
+		} else if lineErr := line.validate(); lineErr != nil {
+			result = "Помилка параметрів лінії: " + lineErr.Error()
+		} else {
+			devices := []SwitchgearData{*breaker, *disconnector, *ct}
+			result = calculateResultsWithDensity(
+				fTf, fTau, fSm, fVoltage, fTm, fKZ, *transformer, devices, conditions, line,
+				allCableData, allEconomicDensity, allCorrectionFactors, allConductorImpedance,
+			)
 		}
+
+		// Формуємо дані для шаблону (поля Завдання 3 залишаються за замовчуванням)
+		data := defaultPageData()
+		data.TimeTf = timeTf
+		data.TimeTau = timeTau
+		data.PowerSm = powerSm
+		data.Voltage = voltage
+		data.TimeTm = timeTm
+		data.PowerKZ = powerKZ
+		data.Result12 = result
+
+		data.Laying = laying
+		data.AmbientTemp = ambientTemp
+		data.SoilResistivity = soilResistivity
+		data.CablesCount = cablesCount
+		data.LineLength = lineLength
+		data.PowerFactor = powerFactor
+		data.AllowedDrop = allowedDrop
+
+		data.Transformer12 = transformerName
+		data.Breaker = breakerName
+		data.Disconnector = disconnectorName
+		data.CurrentTransformer = ctName
 		tmpl.Execute(w, data)
 		return
 	}
@@ -701,87 +1022,149 @@ func handlerCalculate12(w http.ResponseWriter, r *http.Request) {
 func handlerCalculate3(w http.ResponseWriter, r *http.Request) {
 	if r.Method == http.MethodPost {
 		// Зчитуємо поля
-		uKmax := r.FormValue("uKmax")
-		uVn := r.FormValue("uVn")
-		uNn := r.FormValue("uNn")
-		sNomT := r.FormValue("sNomT")
+		transformerName := r.FormValue("transformer")
+		ukMin := r.FormValue("ukMin")
+		ukNom := r.FormValue("ukNom")
+		ukMax := r.FormValue("ukMax")
+		tapRange := r.FormValue("tapRange")
 		rcN := r.FormValue("rc_n")
 		xcN := r.FormValue("xc_n")
 		rcMin := r.FormValue("rc_min")
 		xcMin := r.FormValue("xc_min")
-		r0 := r.FormValue("r0")
-		x0 := r.FormValue("x0")
-
-		section1_2 := r.FormValue("section1_2")
-		section2_3 := r.FormValue("section2_3")
-		section4_5 := r.FormValue("section4_5")
-		section5_6 := r.FormValue("section5_6")
-		section6_7 := r.FormValue("section6_7")
-		section7_8 := r.FormValue("section7_8")
-		section8_9 := r.FormValue("section8_9")
-		section9_10 := r.FormValue("section9_10")
+		networkJSON := r.FormValue("network")
+		connection := r.FormValue("connection")
+		rc0N := r.FormValue("rc0_n")
+		xc0N := r.FormValue("xc0_n")
+		rc0Min := r.FormValue("rc0_min")
+		xc0Min := r.FormValue("xc0_min")
+		method := r.FormValue("method")
+		protectionJSON := r.FormValue("protection")
+		kRelInst := r.FormValue("kRelInst")
+		kRelTime := r.FormValue("kRelTime")
+		kSelfStart := r.FormValue("kSelfStart")
+		kReturn := r.FormValue("kReturn")
+		timeStep := r.FormValue("timeStep")
+		timeMin := r.FormValue("timeMin")
+		motorsJSON := r.FormValue("motors")
+		sBase := r.FormValue("sBase")
+		meshJSON := r.FormValue("mesh")
+		meshFault := r.FormValue("meshFault")
+		loadsJSON := r.FormValue("loads")
+		uSupply := r.FormValue("uSupply")
 
 		// Парсимо
-		fUKmax, _ := strconv.ParseFloat(uKmax, 64)
-		fUVn, _ := strconv.ParseFloat(uVn, 64)
-		fUNn, _ := strconv.ParseFloat(uNn, 64)
-		fSNomT, _ := strconv.ParseFloat(sNomT, 64)
 		fRcN, _ := strconv.ParseFloat(rcN, 64)
 		fXcN, _ := strconv.ParseFloat(xcN, 64)
 		fRcMin, _ := strconv.ParseFloat(rcMin, 64)
 		fXcMin, _ := strconv.ParseFloat(xcMin, 64)
-		fR0, _ := strconv.ParseFloat(r0, 64)
-		fX0, _ := strconv.ParseFloat(x0, 64)
-
-		fSec12, _ := strconv.ParseFloat(section1_2, 64)
-		fSec23, _ := strconv.ParseFloat(section2_3, 64)
-		fSec45, _ := strconv.ParseFloat(section4_5, 64)
-		fSec56, _ := strconv.ParseFloat(section5_6, 64)
-		fSec67, _ := strconv.ParseFloat(section6_7, 64)
-		fSec78, _ := strconv.ParseFloat(section7_8, 64)
-		fSec89, _ := strconv.ParseFloat(section8_9, 64)
-		fSec910, _ := strconv.ParseFloat(section9_10, 64)
-
-		result := calculateMain(
-			fUKmax, fUVn, fUNn, fSNomT,
-			fRcN, fXcN, fRcMin, fXcMin,
-			fR0, fX0,
-			fSec12, fSec23, fSec45, fSec56,
-			fSec67, fSec78, fSec89, fSec910,
-		)
+		fRc0N, _ := strconv.ParseFloat(rc0N, 64)
+		fXc0N, _ := strconv.ParseFloat(xc0N, 64)
+		fRc0Min, _ := strconv.ParseFloat(rc0Min, 64)
+		fXc0Min, _ := strconv.ParseFloat(xc0Min, 64)
+
+		earth := EarthFaultParams{
+			Connection: connection,
+			Rc0N:       fRc0N,
+			Xc0N:       fXc0N,
+			Rc0Min:     fRc0Min,
+			Xc0Min:     fXc0Min,
+		}
+
+		fKRelInst, _ := strconv.ParseFloat(kRelInst, 64)
+		fKRelTime, _ := strconv.ParseFloat(kRelTime, 64)
+		fKSelfStart, _ := strconv.ParseFloat(kSelfStart, 64)
+		fKReturn, _ := strconv.ParseFloat(kReturn, 64)
+		fTimeStep, _ := strconv.ParseFloat(timeStep, 64)
+		fTimeMin, _ := strconv.ParseFloat(timeMin, 64)
+		fSBase, _ := strconv.ParseFloat(sBase, 64)
+		fUSupply, _ := strconv.ParseFloat(uSupply, 64)
+
+		settings := ProtectionSettings{
+			KRelInst:   fKRelInst,
+			KRelTime:   fKRelTime,
+			KSelfStart: fKSelfStart,
+			KReturn:    fKReturn,
+			TimeStep:   fTimeStep,
+			TimeMin:    fTimeMin,
+		}
 
-		// Повертаємо результат у шаблон
-		data := PageData{
-			// Поля для Завдань 1-2 (залишимо дефолтні)
-			CurrentIk: "2500",
-			TimeTf:    "2.5",
-			PowerSm:   "1300",
-			Voltage:   "10",
-			TimeTm:    "4000",
-			PowerKZ:   "2000",
-			Result12:  "",
-
-			// Поля для Завдання 3
-			UKmax:       uKmax,
-			UVn:         uVn,
-			UNn:         uNn,
-			SNomT:       sNomT,
-			RcN:         rcN,
-			XcN:         xcN,
-			RcMin:       rcMin,
-			XcMin:       xcMin,
-			R0:          r0,
-			X0:          x0,
-			Section1_2:  section1_2,
-			Section2_3:  section2_3,
-			Section4_5:  section4_5,
-			Section5_6:  section5_6,
-			Section6_7:  section6_7,
-			Section7_8:  section7_8,
-			Section8_9:  section8_9,
-			Section9_10: section9_10,
-			Result3:     result,
+		var result string
+		transformer := findTransformer(transformerName, allTransformers)
+		network, err := parseRadialNetwork(networkJSON)
+		if transformer == nil {
+			result = "Трансформатор не знайдено в каталозі: " + transformerName
+		} else if tapErr := transformer.applyTapSettings(ukMin, ukNom, ukMax, tapRange); tapErr != nil {
+			result = "Помилка параметрів РПН: " + tapErr.Error()
+		} else if err != nil {
+			result = "Помилка опису мережі: " + err.Error()
+		} else {
+			report, circuit := calculateMain(*transformer, fRcN, fXcN, fRcMin, fXcMin, network)
+			sections := []string{
+				report,
+				reportEarthFaults(circuit, earth),
+			}
+			// Додаткові розділи розраховуються лише за наявності вхідних даних
+			if strings.TrimSpace(motorsJSON) != "" {
+				sections = append(sections, reportMotors(circuit, motorsJSON))
+			}
+			if strings.TrimSpace(protectionJSON) != "" {
+				sections = append(sections, reportProtection(circuit, protectionJSON, settings))
+			}
+			if strings.TrimSpace(sBase) != "" {
+				sections = append(sections, reportPerUnit(circuit, fSBase))
+			}
+			if strings.TrimSpace(meshJSON) != "" {
+				// Струми КЗ в амперах від базисної потужності не залежать, тому без Sб береться типове значення
+				meshBase := fSBase
+				if strings.TrimSpace(sBase) == "" {
+					meshBase = defaultSBase
+				}
+				sections = append(sections, reportMesh(meshJSON, strings.TrimSpace(meshFault), meshBase))
+			}
+			if strings.TrimSpace(loadsJSON) != "" {
+				sections = append(sections, reportLoadFlow(network, loadsJSON, fUSupply))
+			}
+			if method == "iec" {
+				sections = append(sections, reportIEC60909(circuit))
+			}
+			result = strings.Join(sections, "\n")
 		}
+
+		// Повертаємо результат у шаблон (поля Завдань 1-2 залишаються за замовчуванням)
+		data := defaultPageData()
+		data.Transformer3 = transformerName
+		data.UkMin = ukMin
+		data.UkNom = ukNom
+		data.UkMax = ukMax
+		data.TapRange = tapRange
+		data.RcN = rcN
+		data.XcN = xcN
+		data.RcMin = rcMin
+		data.XcMin = xcMin
+		data.Network = networkJSON
+
+		data.Connection = connection
+		data.Rc0N = rc0N
+		data.Xc0N = xc0N
+		data.Rc0Min = rc0Min
+		data.Xc0Min = xc0Min
+		data.Method = method
+
+		data.Protection = protectionJSON
+		data.KRelInst = kRelInst
+		data.KRelTime = kRelTime
+		data.KSelfStart = kSelfStart
+		data.KReturn = kReturn
+		data.TimeStep = timeStep
+		data.TimeMin = timeMin
+		data.Motors = motorsJSON
+		data.SBase = sBase
+		data.Mesh = meshJSON
+		data.MeshFault = meshFault
+		data.Loads = loadsJSON
+		data.USupply = uSupply
+
+		data.Result3 = result
 		tmpl.Execute(w, data)
 		return
 	}
@@ -802,6 +1185,26 @@ func main() {
 		log.Fatalf("Помилка завантаження economic_density.json: %v", err)
 	}
 
+	allCorrectionFactors, err = loadCorrectionFactors("correction_factors.json")
+	if err != nil {
+		log.Fatalf("Помилка завантаження correction_factors.json: %v", err)
+	}
+
+	allConductorImpedance, err = loadConductorImpedance("conductor_impedance.json")
+	if err != nil {
+		log.Fatalf("Помилка завантаження conductor_impedance.json: %v", err)
+	}
+
+	allTransformers, err = loadTransformers("transformers.json")
+	if err != nil {
+		log.Fatalf("Помилка завантаження transformers.json: %v", err)
+	}
+
+	allSwitchgear, err = loadSwitchgear("switchgear.json")
+	if err != nil {
+		log.Fatalf("Помилка завантаження switchgear.json: %v", err)
+	}
+
 	// Парсимо HTML-шаблон
 	tmpl, err = template.ParseFiles("template.html")
 	if err != nil {
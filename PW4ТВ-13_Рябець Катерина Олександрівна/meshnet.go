@@ -0,0 +1,320 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/cmplx"
+	"strings"
+)
+
+// Шини замкненої мережі з середньою напругою ступеня
+type MeshBus struct {
+	Name string  `json:"name"`
+	U    float64 `json:"u"` // Середня напруга ступеня, кВ
+}
+
+// Вітка замкненої мережі: лінія або трансформатор з каталогу
+type MeshBranch struct {
+	Type        string  `json:"type"` // line або transformer
+	From        string  `json:"from"` // Для трансформатора — шини ВН
+	To          string  `json:"to"`   // Для трансформатора — шини НН
+	Length      float64 `json:"length,omitempty"`
+	R0          float64 `json:"r0,omitempty"`
+	X0          float64 `json:"x0,omitempty"`
+	Transformer string  `json:"transformer,omitempty"` // Тип трансформатора з transformers.json
+}
+
+// Джерело живлення (система) з опором, приведеним до напруги шин
+type MeshSource struct {
+	Bus string  `json:"bus"`
+	R   float64 `json:"r"` // Ом
+	X   float64 `json:"x"` // Ом
+}
+
+// Замкнена мережа: шини, вітки та джерела
+type MeshNetwork struct {
+	Buses    []MeshBus    `json:"buses"`
+	Branches []MeshBranch `json:"branches"`
+	Sources  []MeshSource `json:"sources"`
+}
+
+// Струм у вітці або джерелі при КЗ на шинах
+type MeshBranchCurrent struct {
+	Name    string
+	From    string
+	To      string
+	U       float64 // Напруга ступеня, до якої приведено струм, кВ
+	IPU     float64 // Струм, в.о.
+	I       float64 // Струм, А
+	Forward bool    // Напрям від From до To
+}
+
+// Результат розрахунку КЗ на шинах замкненої мережі
+type MeshFaultResult struct {
+	Bus      string
+	Z        complex128 // Власний опір вузла Zkk, в.о.
+	IPU      float64    // I(3), в.о.
+	I        float64    // I(3), А
+	Voltages []float64  // Залишкові напруги шин, в.о. (у порядку Buses)
+	Branches []MeshBranchCurrent
+}
+
+// Мережа за замовчуванням: кільце 110 кВ з трьох підстанцій, два джерела
+// та трансформатор ТМН-6300/110, що живить шини 1 радіальної мережі
+const defaultMeshJSON = `{
+  "buses": [
+    {"name": "ПС1", "u": 115},
+    {"name": "ПС2", "u": 115},
+    {"name": "ПС3", "u": 115},
+    {"name": "1", "u": 11}
+  ],
+  "branches": [
+    {"type": "line", "from": "ПС1", "to": "ПС2", "length": 25, "r0": 0.249, "x0": 0.427},
+    {"type": "line", "from": "ПС2", "to": "ПС3", "length": 18, "r0": 0.249, "x0": 0.427},
+    {"type": "line", "from": "ПС1", "to": "ПС3", "length": 30, "r0": 0.249, "x0": 0.427},
+    {"type": "transformer", "from": "ПС2", "to": "1", "transformer": "ТМН-6300/110"}
+  ],
+  "sources": [
+    {"bus": "ПС1", "r": 10.65, "x": 24.02},
+    {"bus": "ПС3", "r": 12.0, "x": 30.0}
+  ]
+}`
+
+// Зчитування опису замкненої мережі з JSON та перевірка посилань на шини
+func parseMeshNetwork(text string) (MeshNetwork, error) {
+	var mesh MeshNetwork
+	if err := json.Unmarshal([]byte(text), &mesh); err != nil {
+		return mesh, fmt.Errorf("некоректний JSON замкненої мережі: %v", err)
+	}
+	if len(mesh.Buses) == 0 {
+		return mesh, errors.New("не задано шини замкненої мережі")
+	}
+	if len(mesh.Sources) == 0 {
+		return mesh, errors.New("не задано жодного джерела живлення")
+	}
+
+	buses := map[string]bool{}
+	for _, b := range mesh.Buses {
+		if b.Name == "" || b.U <= 0 {
+			return mesh, fmt.Errorf("шини %q: не задано назву або напругу", b.Name)
+		}
+		if buses[b.Name] {
+			return mesh, fmt.Errorf("шини %s задано двічі", b.Name)
+		}
+		buses[b.Name] = true
+	}
+	for i, b := range mesh.Branches {
+		if !buses[b.From] || !buses[b.To] {
+			return mesh, fmt.Errorf("вітка %d: шини %s або %s не задано", i+1, b.From, b.To)
+		}
+		if b.From == b.To {
+			return mesh, fmt.Errorf("вітка %d: початок і кінець збігаються", i+1)
+		}
+		switch b.Type {
+		case "line":
+			if b.Length <= 0 || b.R0 < 0 || b.X0 < 0 || b.R0+b.X0 == 0 {
+				return mesh, fmt.Errorf("лінія %s-%s: довжина та опір мають бути додатними", b.From, b.To)
+			}
+		case "transformer":
+			if findTransformer(b.Transformer, allTransformers) == nil {
+				return mesh, fmt.Errorf("трансформатор %s-%s: тип %s не знайдено в каталозі", b.From, b.To, b.Transformer)
+			}
+		default:
+			return mesh, fmt.Errorf("вітка %s-%s: невідомий тип %q (line або transformer)", b.From, b.To, b.Type)
+		}
+	}
+	for _, s := range mesh.Sources {
+		if !buses[s.Bus] {
+			return mesh, fmt.Errorf("джерело: шини %s не задано", s.Bus)
+		}
+		if s.R < 0 || s.X <= 0 {
+			return mesh, fmt.Errorf("джерело на шинах %s: опір має бути додатним", s.Bus)
+		}
+	}
+	return mesh, nil
+}
+
+// Опір вітки у відносних одиницях на ступені напруги шин From
+func (b MeshBranch) impedancePU(sBase, u float64) complex128 {
+	zone := newPerUnitZone("", sBase, u)
+	if b.Type == "transformer" {
+		rt, xt := findTransformer(b.Transformer, allTransformers).impedance(u)
+		return zone.toPU(rt, xt)
+	}
+	return zone.toPU(b.Length*b.R0, b.Length*b.X0)
+}
+
+// Назва вітки для звіту
+func (b MeshBranch) label() string {
+	if b.Type == "transformer" {
+		return "Т " + b.Transformer
+	}
+	return fmt.Sprintf("Лінія %.1f км", b.Length)
+}
+
+// Обернення комплексної матриці методом Гаусса–Жордана з вибором головного елемента
+func invertComplexMatrix(a [][]complex128) ([][]complex128, error) {
+	n := len(a)
+	m := make([][]complex128, n)
+	inv := make([][]complex128, n)
+	for i := range a {
+		m[i] = append([]complex128(nil), a[i]...)
+		inv[i] = make([]complex128, n)
+		inv[i][i] = 1
+	}
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if cmplx.Abs(m[row][col]) > cmplx.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if cmplx.Abs(m[pivot][col]) < 1e-12 {
+			return nil, errors.New("матриця провідностей вироджена")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+		inv[col], inv[pivot] = inv[pivot], inv[col]
+
+		p := m[col][col]
+		for j := 0; j < n; j++ {
+			m[col][j] /= p
+			inv[col][j] /= p
+		}
+		for row := 0; row < n; row++ {
+			if row == col || m[row][col] == 0 {
+				continue
+			}
+			f := m[row][col]
+			for j := 0; j < n; j++ {
+				m[row][j] -= f * m[col][j]
+				inv[row][j] -= f * inv[col][j]
+			}
+		}
+	}
+	return inv, nil
+}
+
+// Матриця вузлових опорів Zbus (в.о.) як обернена матриця вузлових провідностей,
+// до якої входять вітки мережі та опори джерел (на землю)
+func (mesh MeshNetwork) zBus(sBase float64) ([][]complex128, map[string]int, error) {
+	index := map[string]int{}
+	for i, b := range mesh.Buses {
+		index[b.Name] = i
+	}
+	n := len(mesh.Buses)
+	y := make([][]complex128, n)
+	for i := range y {
+		y[i] = make([]complex128, n)
+	}
+	for _, b := range mesh.Branches {
+		i, j := index[b.From], index[b.To]
+		yb := 1 / b.impedancePU(sBase, mesh.Buses[i].U)
+		y[i][i] += yb
+		y[j][j] += yb
+		y[i][j] -= yb
+		y[j][i] -= yb
+	}
+	for _, s := range mesh.Sources {
+		i := index[s.Bus]
+		y[i][i] += 1 / newPerUnitZone("", sBase, mesh.Buses[i].U).toPU(s.R, s.X)
+	}
+
+	z, err := invertComplexMatrix(y)
+	if err != nil {
+		return nil, nil, errors.New("частина шин не з'єднана з джерелом живлення")
+	}
+	return z, index, nil
+}
+
+// Трифазне КЗ на кожних шинах замкненої мережі (ЕРС джерел і доаварійні напруги — 1 в.о.)
+// зі струмами у вітках та джерелах
+func calculateMeshFaults(mesh MeshNetwork, sBase float64) ([]MeshFaultResult, error) {
+	z, index, err := mesh.zBus(sBase)
+	if err != nil {
+		return nil, err
+	}
+
+	var results []MeshFaultResult
+	for k, bus := range mesh.Buses {
+		res := MeshFaultResult{Bus: bus.Name, Z: z[k][k]}
+		iF := 1 / z[k][k]
+		res.IPU = cmplx.Abs(iF)
+		res.I = res.IPU * newPerUnitZone("", sBase, bus.U).IBase * 1000.0
+
+		// Напруги шин під час КЗ: Vi = 1 − Zik/Zkk
+		v := make([]complex128, len(mesh.Buses))
+		for i := range v {
+			v[i] = 1 - z[i][k]/z[k][k]
+			res.Voltages = append(res.Voltages, cmplx.Abs(v[i]))
+		}
+
+		current := func(name, from, to string, u float64, i complex128) MeshBranchCurrent {
+			return MeshBranchCurrent{
+				Name:    name,
+				From:    from,
+				To:      to,
+				U:       u,
+				IPU:     cmplx.Abs(i),
+				I:       cmplx.Abs(i) * newPerUnitZone("", sBase, u).IBase * 1000.0,
+				Forward: real(i*cmplx.Conj(iF)) >= 0,
+			}
+		}
+		for _, s := range mesh.Sources {
+			i := index[s.Bus]
+			u := mesh.Buses[i].U
+			iS := (1 - v[i]) / newPerUnitZone("", sBase, u).toPU(s.R, s.X)
+			res.Branches = append(res.Branches, current("Джерело", "E", s.Bus, u, iS))
+		}
+		for _, b := range mesh.Branches {
+			i, j := index[b.From], index[b.To]
+			u := mesh.Buses[i].U
+			iB := (v[i] - v[j]) / b.impedancePU(sBase, u)
+			res.Branches = append(res.Branches, current(b.label(), b.From, b.To, u, iB))
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
+
+// Звіт: струми КЗ на всіх шинах та розподіл струму по вітках для обраних шин
+// (якщо шини не задано — лише струми КЗ)
+func describeMeshFaults(mesh MeshNetwork, results []MeshFaultResult, faultBus string, sBase float64) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Замкнена мережа, метод матриці вузлових опорів (Sб = %g МВА):\n", sBase)
+	sb.WriteString("Шини   Uср, кВ   |Zkk|, в.о.  I(3), в.о.  I(3), А\n")
+	var selected *MeshFaultResult
+	for i, r := range results {
+		fmt.Fprintf(&sb, "%-6s %7.2f  %11.5f  %10.4f  %9.2f\n",
+			r.Bus, mesh.Buses[i].U, cmplx.Abs(r.Z), r.IPU, r.I)
+		if r.Bus == faultBus {
+			selected = &results[i]
+		}
+	}
+	if faultBus == "" {
+		return sb.String()
+	}
+	if selected == nil {
+		fmt.Fprintf(&sb, "Шини %s для розподілу струму КЗ не знайдено\n", faultBus)
+		return sb.String()
+	}
+
+	fmt.Fprintf(&sb, "Розподіл струму при КЗ на шинах %s (I(3) = %.2f А):\n", selected.Bus, selected.I)
+	sb.WriteString("Вітка                 Напрям        Uср, кВ  I, в.о.   I, А\n")
+	for _, b := range selected.Branches {
+		from, to := b.From, b.To
+		if !b.Forward {
+			from, to = to, from
+		}
+		fmt.Fprintf(&sb, "%-21s %-13s %7.2f  %7.4f  %9.2f\n", b.Name, from+"→"+to, b.U, b.IPU, b.I)
+	}
+	sb.WriteString("Залишкові напруги шин:")
+	for i, v := range selected.Voltages {
+		fmt.Fprintf(&sb, " %s = %.3f в.о.", mesh.Buses[i].Name, v)
+		if i < len(selected.Voltages)-1 {
+			sb.WriteString(",")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
@@ -0,0 +1,130 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// Надперехідна ЕРС (в.о.) та ударний коефіцієнт для типів двигунів
+type motorTypeData struct {
+	Name string
+	E    float64
+	Ky   float64
+}
+
+var motorTypes = map[string]motorTypeData{
+	"induction":   {Name: "асинхронні", E: 0.9, Ky: 1.6},
+	"synchronous": {Name: "синхронні", E: 1.1, Ky: 1.82},
+}
+
+// Група однотипних двигунів, приєднаних до вузла мережі
+type MotorGroup struct {
+	Node       string  `json:"node"`
+	Type       string  `json:"type"`        // induction або synchronous
+	Power      float64 `json:"power"`       // Номінальна потужність одного двигуна, кВт
+	Count      int     `json:"count"`       // Кількість двигунів у групі
+	StartRatio float64 `json:"start_ratio"` // Кратність пускового струму kп
+	CosPhi     float64 `json:"cos_phi"`
+	Efficiency float64 `json:"efficiency"` // ККД
+}
+
+// Підживлення КЗ у вузлі від системи та двигунів
+type MotorContribution struct {
+	Node        string
+	IpSystem    float64 // Початковий струм від системи, кА
+	IpMotors    float64 // Початковий струм від двигунів, кА
+	PeakSystem  float64 // Ударний струм від системи, кА
+	PeakMotors  float64 // Ударний струм від двигунів, кА
+	MotorGroups []MotorGroup
+}
+
+// Двигуни за замовчуванням: синхронні на шинах живлення та асинхронні у вузлі 5
+const defaultMotorsJSON = `[
+  {"node": "1", "type": "synchronous", "power": 1600, "count": 2, "start_ratio": 6.0, "cos_phi": 0.9, "efficiency": 0.96},
+  {"node": "5", "type": "induction", "power": 800, "count": 3, "start_ratio": 5.5, "cos_phi": 0.87, "efficiency": 0.95}
+]`
+
+// Зчитування груп двигунів з JSON та перевірка параметрів
+func parseMotorGroups(text string) ([]MotorGroup, error) {
+	var motors []MotorGroup
+	if err := json.Unmarshal([]byte(text), &motors); err != nil {
+		return nil, fmt.Errorf("некоректний JSON двигунів: %v", err)
+	}
+	for i, m := range motors {
+		if _, ok := motorTypes[m.Type]; !ok {
+			return nil, fmt.Errorf("група %d: невідомий тип двигунів %s", i+1, m.Type)
+		}
+		if m.Power <= 0 || m.Count < 1 || m.StartRatio <= 0 || m.CosPhi <= 0 || m.Efficiency <= 0 {
+			return nil, fmt.Errorf("група %d: потужність, кількість, kп, cos φ та ККД мають бути додатними", i+1)
+		}
+	}
+	return motors, nil
+}
+
+// Номінальний струм групи двигунів, А
+func (m MotorGroup) ratedCurrent(u float64) float64 {
+	return float64(m.Count) * m.Power / (math.Sqrt(3.0) * u * m.CosPhi * m.Efficiency)
+}
+
+// Початковий струм підживлення від групи двигунів Iп0 = E”·kп·Iном, кА
+func (m MotorGroup) initialCurrent(u float64) float64 {
+	return motorTypes[m.Type].E * m.StartRatio * m.ratedCurrent(u) / 1000.0
+}
+
+// Початковий та ударний струми КЗ у вузлах з двигунами (нормальний режим)
+func calculateMotorContributions(motors []MotorGroup, faults []NodeFaultCurrents, u float64) ([]MotorContribution, error) {
+	groups := map[string][]MotorGroup{}
+	for _, m := range motors {
+		groups[m.Node] = append(groups[m.Node], m)
+	}
+
+	var result []MotorContribution
+	found := 0
+	for _, f := range faults {
+		nodeMotors, ok := groups[f.Node]
+		if !ok {
+			continue
+		}
+		found++
+		c := MotorContribution{Node: f.Node, MotorGroups: nodeMotors}
+		c.IpSystem = f.Currents.First / 1000.0
+		xr := 0.0
+		if f.RSum > 0 {
+			xr = f.XSum / f.RSum
+		}
+		c.PeakSystem = math.Sqrt(2.0) * peakFactor(xr) * c.IpSystem
+		for _, m := range nodeMotors {
+			ip := m.initialCurrent(u)
+			c.IpMotors += ip
+			c.PeakMotors += math.Sqrt(2.0) * motorTypes[m.Type].Ky * ip
+		}
+		result = append(result, c)
+	}
+	if found != len(groups) {
+		return nil, fmt.Errorf("двигуни приєднано до вузла, якого немає в мережі")
+	}
+	return result, nil
+}
+
+// Таблиця підживлення КЗ від двигунів
+func describeMotorContributions(contributions []MotorContribution, u float64) string {
+	if len(contributions) == 0 {
+		return "Двигуни у вузлах мережі не задано\n"
+	}
+	var sb strings.Builder
+	sb.WriteString("Підживлення КЗ від двигунів:\n")
+	for _, c := range contributions {
+		fmt.Fprintf(&sb, "Вузол %s:\n", c.Node)
+		for _, m := range c.MotorGroups {
+			fmt.Fprintf(&sb, "  %s %d × %.0f кВт: Iном = %.2f А, kп = %.2f, Iп0 = %.3f кА\n",
+				motorTypes[m.Type].Name, m.Count, m.Power, m.ratedCurrent(u), m.StartRatio, m.initialCurrent(u))
+		}
+		fmt.Fprintf(&sb, "  Iп0: система %.3f кА + двигуни %.3f кА = %.3f кА\n",
+			c.IpSystem, c.IpMotors, c.IpSystem+c.IpMotors)
+		fmt.Fprintf(&sb, "  iу: система %.3f кА + двигуни %.3f кА = %.3f кА\n",
+			c.PeakSystem, c.PeakMotors, c.PeakSystem+c.PeakMotors)
+	}
+	return sb.String()
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Ділянка радіальної мережі між двома вузлами
+type NetworkBranch struct {
+	From   string  `json:"from"`
+	To     string  `json:"to"`
+	Length float64 `json:"length"`            // Довжина, км
+	R0     float64 `json:"r0"`                // Активний опір, Ом/км
+	X0     float64 `json:"x0"`                // Реактивний опір, Ом/км
+	R0Zero float64 `json:"r0_zero,omitempty"` // Активний опір нульової послідовності, Ом/км
+	X0Zero float64 `json:"x0_zero,omitempty"` // Реактивний опір нульової послідовності, Ом/км
+}
+
+// Радіальна мережа: корінь (шини живлення) та ділянки, що утворюють дерево
+type RadialNetwork struct {
+	Root     string          `json:"root"`
+	Branches []NetworkBranch `json:"branches"`
+}
+
+// Сумарний опір від шин живлення до вузла
+type NodeImpedance struct {
+	Node   string
+	Parent string
+	Depth  int     // Кількість ділянок від кореня
+	Length float64 // Довжина шляху від кореня, км
+	R      float64 // Активний опір шляху, Ом
+	X      float64 // Реактивний опір шляху, Ом
+	RZero  float64 // Активний опір нульової послідовності шляху, Ом
+	XZero  float64 // Реактивний опір нульової послідовності шляху, Ом
+}
+
+// Струми КЗ у вузлі мережі
+type NodeFaultCurrents struct {
+	NodeImpedance
+	RSum     float64 // RΣ нормального режиму, Ом
+	XSum     float64
+	ZSum     float64
+	RSumMin  float64 // RΣ мінімального режиму, Ом
+	XSumMin  float64
+	ZSumMin  float64
+	Currents FourFloats // I(3), I(2) нормального та мінімального режимів, А
+}
+
+// Мережа за замовчуванням: послідовність ділянок 1-2 ... 9-10 з однаковими погонними опорами
+// (вузли 3 і 4 з'єднані без опору;
+// опори нульової послідовності не задані — використовуються типові співвідношення)
+const defaultNetworkJSON = `{
+  "root": "1",
+  "branches": [
+    {"from": "1", "to": "2", "length": 0.2, "r0": 0.64, "x0": 0.363},
+    {"from": "2", "to": "3", "length": 0.35, "r0": 0.64, "x0": 0.363},
+    {"from": "3", "to": "4", "length": 0, "r0": 0.64, "x0": 0.363},
+    {"from": "4", "to": "5", "length": 0.2, "r0": 0.64, "x0": 0.363},
+    {"from": "5", "to": "6", "length": 0.6, "r0": 0.64, "x0": 0.363},
+    {"from": "6", "to": "7", "length": 2.0, "r0": 0.64, "x0": 0.363},
+    {"from": "7", "to": "8", "length": 2.55, "r0": 0.64, "x0": 0.363},
+    {"from": "8", "to": "9", "length": 3.37, "r0": 0.64, "x0": 0.363},
+    {"from": "9", "to": "10", "length": 3.1, "r0": 0.64, "x0": 0.363}
+  ]
+}`
+
+// Зчитування опису мережі з JSON та перевірка, що ділянки утворюють дерево з коренем Root
+func parseRadialNetwork(text string) (RadialNetwork, error) {
+	var network RadialNetwork
+	if err := json.Unmarshal([]byte(text), &network); err != nil {
+		return network, fmt.Errorf("некоректний JSON мережі: %v", err)
+	}
+	if network.Root == "" {
+		return network, errors.New("не задано кореневий вузол мережі (root)")
+	}
+
+	parents := map[string]string{}
+	for i, b := range network.Branches {
+		if b.From == "" || b.To == "" {
+			return network, fmt.Errorf("ділянка %d: не задано вузли from/to", i+1)
+		}
+		if b.Length < 0 || b.R0 < 0 || b.X0 < 0 || b.R0Zero < 0 || b.X0Zero < 0 {
+			return network, fmt.Errorf("ділянка %s-%s: довжина та опори не можуть бути від'ємними", b.From, b.To)
+		}
+		if b.To == network.Root {
+			return network, fmt.Errorf("ділянка %s-%s: кореневий вузол не може мати живлення", b.From, b.To)
+		}
+		if _, ok := parents[b.To]; ok {
+			return network, fmt.Errorf("вузол %s живиться більше ніж від однієї ділянки — мережа не радіальна", b.To)
+		}
+		parents[b.To] = b.From
+	}
+
+	// Кожен вузол має досягати кореня без циклів
+	for node := range parents {
+		seen := map[string]bool{node: true}
+		for current := node; current != network.Root; {
+			parent, ok := parents[current]
+			if !ok {
+				return network, fmt.Errorf("вузол %s не з'єднаний з коренем %s", current, network.Root)
+			}
+			if seen[parent] {
+				return network, fmt.Errorf("мережа містить цикл через вузол %s", parent)
+			}
+			seen[parent] = true
+			current = parent
+		}
+	}
+	return network, nil
+}
+
+// Погонні опори нульової послідовності; якщо не задані — типові співвідношення
+// для ліній з поверненням струму через землю: r0н = r0 + 0,15 Ом/км, x0н = 3,5·x0
+func (b NetworkBranch) zeroSequence() (float64, float64) {
+	if b.R0Zero == 0 && b.X0Zero == 0 {
+		return b.R0 + 0.15, 3.5 * b.X0
+	}
+	return b.R0Zero, b.X0Zero
+}
+
+// Опори шляхів від кореня до кожного вузла (обхід дерева в глибину)
+func (n RadialNetwork) pathImpedances() []NodeImpedance {
+	children := map[string][]NetworkBranch{}
+	for _, b := range n.Branches {
+		children[b.From] = append(children[b.From], b)
+	}
+
+	var result []NodeImpedance
+	var walk func(node NodeImpedance)
+	walk = func(node NodeImpedance) {
+		result = append(result, node)
+		for _, b := range children[node.Node] {
+			r0Zero, x0Zero := b.zeroSequence()
+			walk(NodeImpedance{
+				Node:   b.To,
+				Parent: node.Node,
+				Depth:  node.Depth + 1,
+				Length: node.Length + b.Length,
+				R:      node.R + b.Length*b.R0,
+				X:      node.X + b.Length*b.X0,
+				RZero:  node.RZero + b.Length*r0Zero,
+				XZero:  node.XZero + b.Length*x0Zero,
+			})
+		}
+	}
+	walk(NodeImpedance{Node: n.Root})
+	return result
+}
+
+// Струми трифазного та двофазного КЗ у кожному вузлі мережі
+// для опорів шин живлення в нормальному та мінімальному режимах
+func calculateNetworkFaults(
+	network RadialNetwork,
+	uNn float64,
+	rShN, xShN, rShNMin, xShNMin float64,
+) []NodeFaultCurrents {
+	var result []NodeFaultCurrents
+	for _, node := range network.pathImpedances() {
+		zVals := calculateZsumN(node.R, node.X, rShN, xShN, rShNMin, xShNMin)
+		result = append(result, NodeFaultCurrents{
+			NodeImpedance: node,
+			RSum:          zVals.First,
+			XSum:          zVals.Second,
+			ZSum:          zVals.Third,
+			RSumMin:       zVals.Fourth,
+			XSumMin:       zVals.Fifth,
+			ZSumMin:       zVals.Sixth,
+			Currents:      calculateI(uNn, zVals.Third, zVals.Sixth),
+		})
+	}
+	return result
+}
+
+// Таблиця струмів КЗ у вузлах мережі
+func describeNetworkFaults(faults []NodeFaultCurrents) string {
+	var sb strings.Builder
+	sb.WriteString("Струми КЗ у вузлах мережі:\n")
+	sb.WriteString("Вузол  Від    L, км   ZΣ.н, Ом  ZΣ.мін, Ом  I(3), А    I(2), А    I(3)min, А  I(2)min, А\n")
+	for _, f := range faults {
+		fmt.Fprintf(&sb, "%-6s %-6s %6.2f  %8.2f  %10.2f  %9.2f  %9.2f  %10.2f  %10.2f\n",
+			f.Node, f.Parent, f.Length, f.ZSum, f.ZSumMin,
+			f.Currents.First, f.Currents.Second, f.Currents.Third, f.Currents.Fourth)
+	}
+	return sb.String()
+}
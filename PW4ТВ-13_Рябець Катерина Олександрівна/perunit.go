@@ -0,0 +1,147 @@
+package main
+
+import (
+	"fmt"
+	"math"
+	"math/cmplx"
+	"strings"
+)
+
+// Базисна потужність, якщо її не задано у формі, МВА
+const defaultSBase = 100.0
+
+// Ступінь напруги з базисними величинами
+type PerUnitZone struct {
+	Name  string
+	UBase float64 // Базисна напруга, кВ
+	ZBase float64 // Базисний опір, Ом
+	IBase float64 // Базисний струм, кА
+}
+
+// Система відносних одиниць: базисна потужність та ступені напруги ВН і НН
+type PerUnitSystem struct {
+	SBase float64 // Базисна потужність, МВА
+	HV    PerUnitZone
+	LV    PerUnitZone
+}
+
+// Елемент схеми заміщення в іменованих та відносних одиницях
+type PerUnitElement struct {
+	Name string
+	Zone string
+	R, X float64 // Ом на своєму ступені напруги
+	Z    complex128
+	ZMin complex128 // Для мінімального режиму (відрізняється лише для системи)
+}
+
+// Результат розрахунку КЗ у вузлі у відносних одиницях
+type PerUnitNodeResult struct {
+	Node   string
+	Z      complex128 // Сумарний опір нормального режиму, в.о.
+	ZMin   complex128 // Сумарний опір мінімального режиму, в.о.
+	IPU    float64    // I(3) нормального режиму, в.о.
+	IPUMin float64    // I(3) мінімального режиму, в.о.
+	I      float64    // I(3) нормального режиму, А
+	IMin   float64    // I(3) мінімального режиму, А
+}
+
+// Базисні величини ступеня напруги
+func newPerUnitZone(name string, sBase, uBase float64) PerUnitZone {
+	return PerUnitZone{
+		Name:  name,
+		UBase: uBase,
+		ZBase: uBase * uBase / sBase,
+		IBase: sBase / (math.Sqrt(3.0) * uBase),
+	}
+}
+
+// Система відносних одиниць: базисна напруга ВН задається, базисна напруга НН
+// визначається через коефіцієнт трансформації трансформатора
+func newPerUnitSystem(sBase, uBaseHV float64, t TransformerData) PerUnitSystem {
+	return PerUnitSystem{
+		SBase: sBase,
+		HV:    newPerUnitZone("ВН", sBase, uBaseHV),
+		LV:    newPerUnitZone("НН", sBase, uBaseHV*t.ULV/t.UHV),
+	}
+}
+
+// Переведення опору з Ом у відносні одиниці ступеня
+func (z PerUnitZone) toPU(r, x float64) complex128 {
+	return complex(r/z.ZBase, x/z.ZBase)
+}
+
+// Переведення схеми заміщення у відносні одиниці та розрахунок КЗ у вузлах
+// (ЕРС — 1 в.о. ступеня НН). Трансформатор береться в тих самих положеннях РПН,
+// що й у розрахунку в іменованих одиницях: maxTap для нормального режиму,
+// minTap для мінімального. Опори ступеня ВН приводяться до ступеня НН через
+// коефіцієнт відгалуження t = Uв.відг / Uв.ном (опір ділиться на t²)
+func calculatePerUnit(
+	ps PerUnitSystem,
+	rcN, xcN, rcMin, xcMin float64,
+	t TransformerData,
+	maxTap, minTap TapFaultCase,
+	network RadialNetwork,
+) ([]PerUnitElement, []PerUnitNodeResult) {
+	elements := []PerUnitElement{
+		{Name: "Система", Zone: ps.HV.Name, R: rcN, X: xcN, Z: ps.HV.toPU(rcN, xcN), ZMin: ps.HV.toPU(rcMin, xcMin)},
+	}
+	zMaxTap := ps.HV.toPU(maxTap.Rt, maxTap.Xt)
+	zMinTap := ps.HV.toPU(minTap.Rt, minTap.Xt)
+	elements = append(elements, PerUnitElement{
+		Name: "Тр-р " + maxTap.Tap.Name, Zone: ps.HV.Name, R: maxTap.Rt, X: maxTap.Xt, Z: zMaxTap, ZMin: zMaxTap,
+	})
+	if minTap.Tap.Name != maxTap.Tap.Name {
+		elements = append(elements, PerUnitElement{
+			Name: "Тр-р " + minTap.Tap.Name, Zone: ps.HV.Name, R: minTap.Rt, X: minTap.Xt, Z: zMinTap, ZMin: zMinTap,
+		})
+	}
+	for _, b := range network.Branches {
+		r, x := b.Length*b.R0, b.Length*b.X0
+		elements = append(elements, PerUnitElement{
+			Name: "Лінія " + b.From + "-" + b.To, Zone: ps.LV.Name, R: r, X: x, Z: ps.LV.toPU(r, x), ZMin: ps.LV.toPU(r, x),
+		})
+	}
+	tMax := complex(maxTap.Tap.UHV/t.UHV, 0)
+	tMin := complex(minTap.Tap.UHV/t.UHV, 0)
+	zBus := (elements[0].Z + zMaxTap) / (tMax * tMax)
+	zBusMin := (elements[0].ZMin + zMinTap) / (tMin * tMin)
+
+	var results []PerUnitNodeResult
+	for _, n := range network.pathImpedances() {
+		zLine := ps.LV.toPU(n.R, n.X)
+		res := PerUnitNodeResult{Node: n.Node, Z: zBus + zLine, ZMin: zBusMin + zLine}
+		res.IPU = 1 / cmplx.Abs(res.Z)
+		res.IPUMin = 1 / cmplx.Abs(res.ZMin)
+		res.I = res.IPU * ps.LV.IBase * 1000.0
+		res.IMin = res.IPUMin * ps.LV.IBase * 1000.0
+		results = append(results, res)
+	}
+	return elements, results
+}
+
+// Звіт розрахунку у відносних одиницях з порівнянням зі струмами КЗ у вузлах,
+// наведеними в основному звіті (розрахунок в іменованих одиницях)
+func describePerUnit(
+	ps PerUnitSystem,
+	maxTap, minTap TapFaultCase,
+	elements []PerUnitElement,
+	results []PerUnitNodeResult,
+	ohmic []NodeFaultCurrents,
+) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Розрахунок у відносних одиницях (Sб = %g МВА):\n", ps.SBase)
+	fmt.Fprintf(&sb, "  РПН: нормальний режим — відгалуження %s, мінімальний — %s\n", maxTap.Tap.Name, minTap.Tap.Name)
+	for _, z := range []PerUnitZone{ps.HV, ps.LV} {
+		fmt.Fprintf(&sb, "  Ступінь %s: Uб = %.2f кВ, Zб = %.4f Ом, Iб = %.4f кА\n", z.Name, z.UBase, z.ZBase, z.IBase)
+	}
+	sb.WriteString("Елемент                 Ступінь  R, Ом     X, Ом     r*, в.о.   x*, в.о.\n")
+	for _, e := range elements {
+		fmt.Fprintf(&sb, "%-23s %-8s %8.3f  %8.3f  %9.5f  %9.5f\n", e.Name, e.Zone, e.R, e.X, real(e.Z), imag(e.Z))
+	}
+	sb.WriteString("Вузол  Z*, в.о.   I*, в.о.   I(3), А    I(3)ом, А   I*min, в.о.  I(3)min, А  I(3)min.ом, А\n")
+	for i, r := range results {
+		fmt.Fprintf(&sb, "%-6s %8.5f  %9.4f  %9.2f  %10.2f  %11.4f  %10.2f  %13.2f\n",
+			r.Node, cmplx.Abs(r.Z), r.IPU, r.I, ohmic[i].Currents.First, r.IPUMin, r.IMin, ohmic[i].Currents.Third)
+	}
+	return sb.String()
+}
@@ -0,0 +1,279 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Коефіцієнт узгодження струмів спрацювання послідовних захистів kнс
+const coordinationCoefficient = 1.1
+
+// Мінімальні коефіцієнти чутливості: МТЗ в основній зоні, МТЗ у зоні резервування та відсічки
+const (
+	minSensitivityMain   = 1.5
+	minSensitivityBackup = 1.2
+	minSensitivityInst   = 1.2
+)
+
+// Захисти за замовчуванням: на головній ділянці та двох відгалуженнях мережі за замовчуванням
+const defaultProtectionJSON = `[
+  {"node": "1", "load_current": 150, "ct": "ТОЛ-10-200/5"},
+  {"node": "6", "load_current": 80, "ct": "ТОЛ-10-100/5"},
+  {"node": "8", "load_current": 40, "ct": "ТОЛ-10-50/5"}
+]`
+
+// Комплект максимального струмового захисту на початку ділянки, що відходить від вузла
+type ProtectionDevice struct {
+	Node        string  `json:"node"`         // Вузол встановлення захисту
+	LoadCurrent float64 `json:"load_current"` // Максимальний робочий струм, А
+	CT          string  `json:"ct"`           // Трансформатор струму з каталогу switchgear.json
+}
+
+// Коефіцієнти для розрахунку уставок
+type ProtectionSettings struct {
+	KRelInst   float64 // Коефіцієнт надійності струмової відсічки kн
+	KRelTime   float64 // Коефіцієнт надійності МТЗ kн
+	KSelfStart float64 // Коефіцієнт самозапуску kсзп
+	KReturn    float64 // Коефіцієнт повернення реле kв
+	TimeStep   float64 // Ступінь селективності Δt, с
+	TimeMin    float64 // Витримка часу найвіддаленішого захисту, с
+}
+
+// Уставки та перевірки одного комплекту захисту
+type ProtectionResult struct {
+	Device     ProtectionDevice
+	CTRatio    float64 // Коефіцієнт трансформації трансформатора струму nт
+	Upstream   string  // Вузол попереднього (ближчого до джерела) захисту
+	Downstream []string
+	RemoteEnd  string // Вузол з найменшим струмом КЗ в основній зоні
+
+	IInst      float64 // Струм спрацювання відсічки, А (0 — відсічка не застосовується)
+	IInstRelay float64 // Струм спрацювання реле відсічки, А
+	KSensInst  float64 // Коефіцієнт чутливості відсічки в місці встановлення
+
+	ITime       float64 // Струм спрацювання МТЗ, А
+	ITimeRelay  float64 // Струм спрацювання реле МТЗ, А
+	KSensMain   float64 // Коефіцієнт чутливості МТЗ в кінці основної зони
+	KSensBackup float64 // Коефіцієнт чутливості МТЗ в кінці зони резервування (0 — немає)
+	Time        float64 // Витримка часу МТЗ, с
+
+	Selective bool // Узгодження з попереднім захистом за струмом
+}
+
+// Зчитування комплектів захисту з JSON
+func parseProtectionDevices(text string) ([]ProtectionDevice, error) {
+	var devices []ProtectionDevice
+	if err := json.Unmarshal([]byte(text), &devices); err != nil {
+		return nil, fmt.Errorf("некоректний JSON захистів: %v", err)
+	}
+	return devices, nil
+}
+
+// Розрахунок уставок МТЗ та струмових відсічок, чутливості та селективності
+// для захистів, встановлених у вузлах радіальної мережі
+func calculateProtection(
+	devices []ProtectionDevice,
+	faults []NodeFaultCurrents,
+	settings ProtectionSettings,
+	switchgear []SwitchgearData,
+) ([]ProtectionResult, error) {
+	byNode := map[string]NodeFaultCurrents{}
+	children := map[string][]string{}
+	for _, f := range faults {
+		byNode[f.Node] = f
+		if f.Depth > 0 {
+			children[f.Parent] = append(children[f.Parent], f.Node)
+		}
+	}
+
+	if settings.KRelInst <= 0 || settings.KRelTime <= 0 {
+		return nil, fmt.Errorf("коефіцієнти надійності мають бути додатними")
+	}
+	if settings.KSelfStart <= 0 {
+		return nil, fmt.Errorf("коефіцієнт самозапуску має бути додатним")
+	}
+	if settings.KReturn <= 0 {
+		return nil, fmt.Errorf("коефіцієнт повернення реле має бути додатним")
+	}
+	if settings.TimeStep <= 0 {
+		return nil, fmt.Errorf("ступінь селективності має бути додатним")
+	}
+	if settings.TimeMin < 0 {
+		return nil, fmt.Errorf("витримка часу не може бути від'ємною")
+	}
+
+	results := map[string]*ProtectionResult{}
+	for _, d := range devices {
+		if _, ok := byNode[d.Node]; !ok {
+			return nil, fmt.Errorf("захист у вузлі %s: вузла немає в мережі", d.Node)
+		}
+		if _, ok := results[d.Node]; ok {
+			return nil, fmt.Errorf("у вузлі %s задано більше одного захисту", d.Node)
+		}
+		ct := findSwitchgear(d.CT, "current_transformer", switchgear)
+		if ct == nil || ct.ISecondary == 0 {
+			return nil, fmt.Errorf("захист у вузлі %s: трансформатор струму %s не знайдено в каталозі", d.Node, d.CT)
+		}
+		if d.LoadCurrent <= 0 {
+			return nil, fmt.Errorf("захист у вузлі %s: робочий струм має бути додатним", d.Node)
+		}
+		results[d.Node] = &ProtectionResult{Device: d, CTRatio: ct.INom / ct.ISecondary}
+	}
+
+	// Основна зона захисту — піддерево до наступних захистів (включно з їх вузлами)
+	zone := func(node string) (nodes []string, downstream []string) {
+		var walk func(n string)
+		walk = func(n string) {
+			for _, c := range children[n] {
+				nodes = append(nodes, c)
+				if _, ok := results[c]; ok {
+					downstream = append(downstream, c)
+					continue
+				}
+				walk(c)
+			}
+		}
+		walk(node)
+		return nodes, downstream
+	}
+	// Усі вузли нижче захисту (зона резервування)
+	var subtree func(node string) []string
+	subtree = func(node string) []string {
+		var nodes []string
+		for _, c := range children[node] {
+			nodes = append(nodes, c)
+			nodes = append(nodes, subtree(c)...)
+		}
+		return nodes
+	}
+
+	for node, res := range results {
+		f := byNode[node]
+		zoneNodes, downstream := zone(node)
+		sort.Strings(downstream)
+		res.Downstream = downstream
+
+		// МТЗ: відлаштування від робочого струму з урахуванням самозапуску
+		res.ITime = settings.KRelTime * settings.KSelfStart / settings.KReturn * res.Device.LoadCurrent
+		res.ITimeRelay = res.ITime / res.CTRatio
+
+		// Чутливість МТЗ за I(2)min в кінці основної зони
+		res.RemoteEnd = node
+		minMain := f.Currents.Fourth
+		maxEnd := 0.0
+		for _, n := range zoneNodes {
+			nf := byNode[n]
+			if nf.Currents.Fourth < minMain {
+				minMain = nf.Currents.Fourth
+				res.RemoteEnd = n
+			}
+			_, isDevice := results[n]
+			if isDevice || len(children[n]) == 0 {
+				if nf.Currents.First > maxEnd {
+					maxEnd = nf.Currents.First
+				}
+			}
+		}
+		res.KSensMain = minMain / res.ITime
+
+		// Резервування наступних захистів: найменший струм КЗ у всьому піддереві
+		if len(downstream) > 0 {
+			minBackup := minMain
+			for _, n := range subtree(node) {
+				if byNode[n].Currents.Fourth < minBackup {
+					minBackup = byNode[n].Currents.Fourth
+				}
+			}
+			res.KSensBackup = minBackup / res.ITime
+		}
+
+		// Відсічка: відлаштування від найбільшого струму КЗ в кінці зони
+		if maxEnd > 0 {
+			res.IInst = settings.KRelInst * maxEnd
+			res.IInstRelay = res.IInst / res.CTRatio
+			res.KSensInst = f.Currents.Fourth / res.IInst
+		}
+	}
+
+	// Витримки часу — від найвіддаленіших захистів до джерела
+	var timeOf func(node string) float64
+	timeOf = func(node string) float64 {
+		res := results[node]
+		if res.Time > 0 {
+			return res.Time
+		}
+		t := settings.TimeMin
+		for _, d := range res.Downstream {
+			if dt := timeOf(d) + settings.TimeStep; dt > t {
+				t = dt
+			}
+		}
+		res.Time = t
+		return t
+	}
+
+	var ordered []ProtectionResult
+	for _, f := range faults {
+		res, ok := results[f.Node]
+		if !ok {
+			continue
+		}
+		timeOf(f.Node)
+		for _, d := range res.Downstream {
+			results[d].Upstream = f.Node
+		}
+	}
+	for _, f := range faults {
+		if res, ok := results[f.Node]; ok {
+			res.Selective = true
+			if res.Upstream != "" {
+				res.Selective = results[res.Upstream].ITime >= coordinationCoefficient*res.ITime
+			}
+			ordered = append(ordered, *res)
+		}
+	}
+	return ordered, nil
+}
+
+// Оцінка коефіцієнта чутливості
+func sensitivityVerdict(k, min float64) string {
+	if k >= min {
+		return "чутливий"
+	}
+	return "НЕ чутливий"
+}
+
+// Текстовий звіт з уставками та перевірками захистів
+func describeProtection(results []ProtectionResult, settings ProtectionSettings) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Релейний захист (kн.в = %.2f, kн = %.2f, kсзп = %.2f, kв = %.2f, Δt = %.2f с):\n",
+		settings.KRelInst, settings.KRelTime, settings.KSelfStart, settings.KReturn, settings.TimeStep)
+	for _, r := range results {
+		fmt.Fprintf(&sb, "Захист у вузлі %s (Iроб.max = %.1f А, ТС %s, nт = %.0f):\n",
+			r.Device.Node, r.Device.LoadCurrent, r.Device.CT, r.CTRatio)
+		if r.IInst > 0 {
+			fmt.Fprintf(&sb, "  Струмова відсічка: Iсз = %.2f А, Iср = %.2f А, kч = %.2f (%s)\n",
+				r.IInst, r.IInstRelay, r.KSensInst, sensitivityVerdict(r.KSensInst, minSensitivityInst))
+		} else {
+			sb.WriteString("  Струмова відсічка: не застосовується\n")
+		}
+		fmt.Fprintf(&sb, "  МТЗ: Iсз = %.2f А, Iср = %.2f А, t = %.2f с\n", r.ITime, r.ITimeRelay, r.Time)
+		fmt.Fprintf(&sb, "  Чутливість в основній зоні (вузол %s): kч = %.2f (%s)\n",
+			r.RemoteEnd, r.KSensMain, sensitivityVerdict(r.KSensMain, minSensitivityMain))
+		if len(r.Downstream) > 0 {
+			fmt.Fprintf(&sb, "  Чутливість у зоні резервування (захисти у вузлах %s): kч = %.2f (%s)\n",
+				strings.Join(r.Downstream, ", "), r.KSensBackup, sensitivityVerdict(r.KSensBackup, minSensitivityBackup))
+		}
+		if r.Upstream != "" {
+			verdict := "так"
+			if !r.Selective {
+				verdict = "НІ"
+			}
+			fmt.Fprintf(&sb, "  Селективність із захистом у вузлі %s (Iсз >= %.2f·Iсз, Δt >= %.2f с): %s\n",
+				r.Upstream, coordinationCoefficient, settings.TimeStep, verdict)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"fmt"
+	"math"
+)
+
+// Кутова частота мережі 50 Гц, рад/с
+const omega = 2 * math.Pi * 50
+
+// Ударний струм, аперіодична складова та тепловий імпульс струму КЗ
+type ShortCircuitDynamics struct {
+	Ip0     float64 // Початкове значення періодичної складової, кА
+	XR      float64 // Відношення X/R кола КЗ
+	Ta      float64 // Постійна часу затухання аперіодичної складової, с
+	Ky      float64 // Ударний коефіцієнт
+	IPeak   float64 // Ударний струм iу, кА
+	Tau     float64 // Розрахунковий час розмикання контактів вимикача τ, с
+	IaTau   float64 // Аперіодична складова в момент τ, кА
+	TOff    float64 // Час вимикання КЗ tвідкл, с
+	Bk      float64 // Тепловий імпульс Bк, кА²·с
+	SechMin float64 // Мінімальний переріз за термічною стійкістю, мм²
+}
+
+// Ударний коефіцієнт kу = 1 + e^(-0,01/Ta) для кола з відношенням X/R
+func peakFactor(xr float64) float64 {
+	if xr <= 0 {
+		return 1
+	}
+	return 1 + math.Exp(-0.01*omega/xr)
+}
+
+// Розрахунок ударного струму, аперіодичної складової та теплового імпульсу
+// для періодичної складової ip0 (кА), незмінної протягом КЗ (віддалене КЗ)
+func calculateShortCircuitDynamics(ip0, xr, tau, tOff, thermalCoefficient float64) ShortCircuitDynamics {
+	d := ShortCircuitDynamics{Ip0: ip0, XR: xr, Tau: tau, TOff: tOff}
+	if xr > 0 {
+		d.Ta = xr / omega
+	}
+	d.Ky = peakFactor(xr)
+	if d.Ta > 0 {
+		d.IaTau = math.Sqrt(2.0) * ip0 * math.Exp(-tau/d.Ta)
+	}
+	d.IPeak = math.Sqrt(2.0) * d.Ky * ip0
+	d.Bk = ip0 * ip0 * (tOff + d.Ta)
+	d.SechMin = math.Sqrt(d.Bk) * 1000.0 / thermalCoefficient
+	return d
+}
+
+// Текстовий опис ударного струму та теплового імпульсу
+func describeShortCircuitDynamics(d ShortCircuitDynamics) string {
+	return fmt.Sprintf(
+		"Ударний струм та тепловий імпульс (X/R = %.2f):\n"+
+			"  Ta = %.4f с\n"+
+			"  kу = %.3f\n"+
+			"  iу = %.3f кА\n"+
+			"  iа,τ = %.3f кА (τ = %.3f с)\n"+
+			"  Bк = %.3f кА²·с (tвідкл = %.3f с)\n",
+		d.XR,
+		d.Ta,
+		d.Ky,
+		d.IPeak,
+		d.IaTau, d.Tau,
+		d.Bk, d.TOff,
+	)
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strings"
+)
+
+// Каталожні дані комутаційного апарата або трансформатора струму (файл switchgear.json)
+type SwitchgearData struct {
+	Type       string  `json:"type"` // breaker, disconnector, current_transformer
+	Name       string  `json:"name"`
+	UNom       float64 `json:"u_nom"`                 // Номінальна напруга, кВ
+	INom       float64 `json:"i_nom"`                 // Номінальний (первинний) струм, А
+	ISecondary float64 `json:"i_secondary,omitempty"` // Вторинний струм трансформатора струму, А
+	IBreak     float64 `json:"i_break,omitempty"`     // Номінальний струм вимикання, кА
+	BetaNom    float64 `json:"beta_nom,omitempty"`    // Нормована частка аперіодичної складової, %
+	IPeak      float64 `json:"i_peak"`                // Струм електродинамічної стійкості, кА
+	IThermal   float64 `json:"i_thermal"`             // Струм термічної стійкості, кА
+	TThermal   float64 `json:"t_thermal"`             // Час термічної стійкості, с
+}
+
+// Рядок таблиці перевірки апарата
+type SwitchgearCheck struct {
+	Criterion  string
+	Calculated float64
+	Rated      float64
+	Pass       bool
+}
+
+var allSwitchgear []SwitchgearData
+
+// Зчитування файлу switchgear.json та десеріалізація
+func loadSwitchgear(filename string) ([]SwitchgearData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	var switchgear []SwitchgearData
+	err = json.Unmarshal(bytes, &switchgear)
+	if err != nil {
+		return nil, err
+	}
+	return switchgear, nil
+}
+
+// Пошук апарата заданого типу в каталозі за назвою
+func findSwitchgear(name, deviceType string, switchgear []SwitchgearData) *SwitchgearData {
+	for _, s := range switchgear {
+		if s.Type == deviceType && s.Name == name {
+			return &s
+		}
+	}
+	return nil
+}
+
+// Перевірка апарата за напругою, робочим струмом, стійкістю до струмів КЗ
+// та (для вимикача) здатністю вимикання
+func verifySwitchgear(d SwitchgearData, voltage, iWork float64, dyn ShortCircuitDynamics) []SwitchgearCheck {
+	checks := []SwitchgearCheck{
+		{Criterion: "Uном >= Uмережі, кВ", Calculated: voltage, Rated: d.UNom},
+		{Criterion: "Iном >= Iроб.max, А", Calculated: iWork, Rated: d.INom},
+	}
+	if d.Type == "breaker" {
+		checks = append(checks,
+			SwitchgearCheck{Criterion: "Iвідкл.ном >= Iп,τ, кА", Calculated: dyn.Ip0, Rated: d.IBreak},
+			SwitchgearCheck{
+				Criterion:  "√2·Iвідкл.ном·(1+βном) >= √2·Iп,τ + iа,τ, кА",
+				Calculated: math.Sqrt(2.0)*dyn.Ip0 + dyn.IaTau,
+				Rated:      math.Sqrt(2.0) * d.IBreak * (1 + d.BetaNom/100.0),
+			},
+		)
+	}
+	checks = append(checks,
+		SwitchgearCheck{Criterion: "iдин >= iу, кА", Calculated: dyn.IPeak, Rated: d.IPeak},
+		SwitchgearCheck{Criterion: "Iт²·tт >= Bк, кА²·с", Calculated: dyn.Bk, Rated: d.IThermal * d.IThermal * d.TThermal},
+	)
+	for i := range checks {
+		checks[i].Pass = checks[i].Rated >= checks[i].Calculated
+	}
+	return checks
+}
+
+// Таблиця перевірки обраних апаратів при КЗ на шинах
+func describeSwitchgearChecks(devices []SwitchgearData, voltage, iWork float64, dyn ShortCircuitDynamics) string {
+	var sb strings.Builder
+	sb.WriteString("Перевірка обладнання при КЗ на шинах:\n")
+	for _, d := range devices {
+		fmt.Fprintf(&sb, "%s:\n", d.Name)
+		fmt.Fprintf(&sb, "  %-46s %12s  %12s  %s\n", "Умова", "Розрахункове", "Каталожне", "Висновок")
+		for _, c := range verifySwitchgear(d, voltage, iWork, dyn) {
+			verdict := "так"
+			if !c.Pass {
+				verdict = "НІ"
+			}
+			fmt.Fprintf(&sb, "  %-46s %12.2f  %12.2f  %s\n", c.Criterion, c.Calculated, c.Rated, verdict)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Положення перемикача РПН: напруга обмотки ВН та напруга КЗ на відгалуженні
+type TapPosition struct {
+	Name string
+	UHV  float64 // Напруга обмотки ВН на відгалуженні, кВ
+	Uk   float64 // Напруга КЗ на відгалуженні, %
+}
+
+// Опори та струми КЗ на шинах НН для одного положення РПН
+type TapFaultCase struct {
+	Tap      TapPosition
+	Rt       float64    // Активний опір трансформатора (приведений до Uв на відгалуженні), Ом
+	Xt       float64    // Реактивний опір трансформатора (приведений до Uв на відгалуженні), Ом
+	KPr      float64    // Коефіцієнт приведення до напруги НН
+	ZN       SixFloats  // Rш.н, Xш.н, Zш.н та мінімальні (приведені до НН)
+	Currents FourFloats // I(3), I(2) нормального та мінімального режимів на шинах НН, А
+}
+
+// Крайні та номінальне положення РПН; uк на крайніх відгалуженнях береться
+// з каталогу, а якщо не задана — дорівнює номінальній
+func (t TransformerData) tapPositions() []TapPosition {
+	ukMin, ukMax := t.UkMin, t.UkMax
+	if ukMin == 0 {
+		ukMin = t.Uk
+	}
+	if ukMax == 0 {
+		ukMax = t.Uk
+	}
+	return []TapPosition{
+		{Name: fmt.Sprintf("мін. (-%g %%)", t.TapRange), UHV: t.UHV * (1 - t.TapRange/100.0), Uk: ukMin},
+		{Name: "ном.", UHV: t.UHV, Uk: t.Uk},
+		{Name: fmt.Sprintf("макс. (+%g %%)", t.TapRange), UHV: t.UHV * (1 + t.TapRange/100.0), Uk: ukMax},
+	}
+}
+
+// Заміна каталожних uк та діапазону регулювання РПН значеннями з форми;
+// порожнє поле залишає значення з каталогу
+func (t *TransformerData) applyTapSettings(ukMin, uk, ukMax, tapRange string) error {
+	fields := []struct {
+		text  string
+		name  string
+		value *float64
+	}{
+		{ukMin, "uк на мінімальному відгалуженні", &t.UkMin},
+		{uk, "uк на номінальному відгалуженні", &t.Uk},
+		{ukMax, "uк на максимальному відгалуженні", &t.UkMax},
+		{tapRange, "діапазон регулювання РПН", &t.TapRange},
+	}
+	for _, f := range fields {
+		text := strings.TrimSpace(f.text)
+		if text == "" {
+			continue
+		}
+		v, err := strconv.ParseFloat(text, 64)
+		if err != nil {
+			return fmt.Errorf("%s: некоректне число %q", f.name, text)
+		}
+		*f.value = v
+	}
+	if !(t.Uk > 0) || t.UkMin < 0 || t.UkMax < 0 {
+		return fmt.Errorf("напруга КЗ трансформатора має бути додатною")
+	}
+	if t.TapRange < 0 || t.TapRange >= 100 {
+		return fmt.Errorf("діапазон регулювання РПН має бути в межах від 0 до 100 %%")
+	}
+	return nil
+}
+
+// Опори та струми КЗ на шинах НН для кожного положення РПН
+func calculateTapCases(t TransformerData, rcN, xcN, rcMin, xcMin float64) []TapFaultCase {
+	var cases []TapFaultCase
+	for _, tap := range t.tapPositions() {
+		tt := t
+		tt.UHV, tt.Uk = tap.UHV, tap.Uk
+		rt, xt := tt.impedance(tap.UHV)
+		kPr := calculateK(tap.UHV, t.ULV)
+
+		zsh := calculateZshValues(rcN+rt, xcN, rcMin+rt, xcMin, xt)
+		zN := calculateZshNValues(rcN+rt, zsh.First, rcMin+rt, zsh.Third, kPr)
+		cases = append(cases, TapFaultCase{
+			Tap:      tap,
+			Rt:       rt,
+			Xt:       xt,
+			KPr:      kPr,
+			ZN:       zN,
+			Currents: calculateI(t.ULV, zN.Third, zN.Sixth),
+		})
+	}
+	return cases
+}
+
+// Найнесприятливіші положення РПН: найбільший струм нормального режиму
+// та найменший струм мінімального режиму
+func worstTapCases(cases []TapFaultCase) (TapFaultCase, TapFaultCase) {
+	maxCase, minCase := cases[0], cases[0]
+	for _, c := range cases[1:] {
+		if c.Currents.First > maxCase.Currents.First {
+			maxCase = c
+		}
+		if c.Currents.Third < minCase.Currents.Third {
+			minCase = c
+		}
+	}
+	return maxCase, minCase
+}
+
+// Таблиця струмів КЗ на шинах НН за положеннями РПН
+func describeTapCases(cases []TapFaultCase, maxCase, minCase TapFaultCase) string {
+	var sb strings.Builder
+	sb.WriteString("Струми КЗ на шинах НН з урахуванням РПН:\n")
+	sb.WriteString("Відгалуження     Uв, кВ   uк, %   XТ, Ом   Zш.н, Ом  Zш.н.мін, Ом  I(3), А    I(3)min, А\n")
+	for _, c := range cases {
+		fmt.Fprintf(&sb, "%-16s %7.2f  %6.2f  %7.2f  %8.2f  %12.2f  %9.2f  %10.2f\n",
+			c.Tap.Name, c.Tap.UHV, c.Tap.Uk, c.Xt, c.ZN.Third, c.ZN.Sixth,
+			c.Currents.First, c.Currents.Third)
+	}
+	fmt.Fprintf(&sb, "Найбільший струм (нормальний режим): відгалуження %s, I(3) = %.2f А\n",
+		maxCase.Tap.Name, maxCase.Currents.First)
+	fmt.Fprintf(&sb, "Найменший струм (мінімальний режим): відгалуження %s, I(3)min = %.2f А\n",
+		minCase.Tap.Name, minCase.Currents.Third)
+	return sb.String()
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"os"
+)
+
+// Паспортні дані двообмоткового трансформатора (файл transformers.json)
+type TransformerData struct {
+	Name     string  `json:"name"`
+	SNom     float64 `json:"s_nom"`     // Номінальна потужність, МВА
+	UHV      float64 `json:"u_hv"`      // Номінальна напруга обмотки ВН, кВ
+	ULV      float64 `json:"u_lv"`      // Номінальна напруга обмотки НН, кВ
+	Uk       float64 `json:"uk"`        // Напруга КЗ на номінальному відгалуженні, %
+	UkMin    float64 `json:"uk_min"`    // Напруга КЗ на крайньому мінусовому відгалуженні, %
+	UkMax    float64 `json:"uk_max"`    // Напруга КЗ на крайньому плюсовому відгалуженні, %
+	PLoad    float64 `json:"p_load"`    // Втрати КЗ ΔPк, кВт
+	PNoLoad  float64 `json:"p_noload"`  // Втрати неробочого ходу ΔPх, кВт
+	TapRange float64 `json:"tap_range"` // Діапазон регулювання ±, %
+	TapSteps int     `json:"tap_steps"` // Кількість ступенів регулювання в кожен бік
+}
+
+var allTransformers []TransformerData
+
+// Зчитування файлу transformers.json та десеріалізація
+func loadTransformers(filename string) ([]TransformerData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	var transformers []TransformerData
+	err = json.Unmarshal(bytes, &transformers)
+	if err != nil {
+		return nil, err
+	}
+	return transformers, nil
+}
+
+// Пошук трансформатора в каталозі за назвою
+func findTransformer(name string, transformers []TransformerData) *TransformerData {
+	for _, t := range transformers {
+		if t.Name == name {
+			return &t
+		}
+	}
+	return nil
+}
+
+// Активний опір трансформатора Rт, Ом (ΔPк у кВт, U у кВ, S у МВА)
+func calculateRtValue(pLoad float64, u float64, sNomT float64) float64 {
+	if u == 0.0 || sNomT == 0.0 {
+		return 0.0
+	}
+	return pLoad * math.Pow(u, 2) / (1000.0 * math.Pow(sNomT, 2))
+}
+
+// Активний та реактивний опори трансформатора, приведені до напруги u
+func (t TransformerData) impedance(u float64) (float64, float64) {
+	zt := calculateXtValue(t.Uk, u, t.SNom)
+	rt := calculateRtValue(t.PLoad, u, t.SNom)
+	return rt, math.Sqrt(math.Max(zt*zt-rt*rt, 0))
+}
+
+// Чи відповідає обмотка НН напрузі мережі: Uнн від Uмережі до 1,1·Uмережі
+// (обмотку НН виконують на 5-10 % вище номінальної напруги мережі)
+func (t TransformerData) matchesNetwork(voltage float64) bool {
+	return t.ULV >= voltage*0.999 && t.ULV <= voltage*1.101
+}
+
+// Текстовий опис паспортних даних трансформатора
+func describeTransformer(t TransformerData) string {
+	return fmt.Sprintf(
+		"Трансформатор %s: Sном = %.1f МВА, %.1f/%.1f кВ, uк = %.2f %% (%.2f…%.2f %%), ΔPк = %.1f кВт, ΔPх = %.1f кВт, РПН ±%.1f %% (±%d ступенів)\n",
+		t.Name, t.SNom, t.UHV, t.ULV, t.Uk, t.UkMin, t.UkMax, t.PLoad, t.PNoLoad, t.TapRange, t.TapSteps,
+	)
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"sort"
+	"strings"
+)
+
+// Погонні опори жили кабелю певного перерізу
+type SectionImpedance struct {
+	Sech int     `json:"sech"`
+	R0   float64 `json:"r0"` // Активний опір, Ом/км
+	X0   float64 `json:"x0"` // Реактивний опір, Ом/км
+}
+
+// Погонні опори для матеріалу провідника (файл conductor_impedance.json)
+type ConductorImpedanceData struct {
+	Conductor string             `json:"conductor"`
+	Sections  []SectionImpedance `json:"sections"`
+}
+
+// Параметри лінії для перевірки втрати напруги
+type LineParams struct {
+	Length      float64 // Довжина лінії, км
+	PowerFactor float64 // Коефіцієнт потужності навантаження cos φ
+	AllowedDrop float64 // Допустима втрата напруги, %
+	Cables      int     // Кількість паралельних кабелів у лінії
+}
+
+// Результат перевірки втрати напруги
+type VoltageDropResult struct {
+	Sech       int // Переріз після перевірки, мм²
+	Impedance  SectionImpedance
+	DropPct    float64 // Втрата напруги, %
+	LossesKW   float64 // Втрати активної потужності, кВт
+	Upsized    bool    // Переріз збільшено через втрату напруги
+	Acceptable bool    // Втрата напруги в допустимих межах
+}
+
+var allConductorImpedance []ConductorImpedanceData
+
+// Перевірка параметрів лінії перед розрахунком втрати напруги
+func (l LineParams) validate() error {
+	if l.PowerFactor <= 0 || l.PowerFactor > 1 {
+		return errors.New("cos φ має бути в межах 0 < cos φ ≤ 1")
+	}
+	if l.Length < 0 {
+		return errors.New("довжина лінії не може бути від'ємною")
+	}
+	if l.AllowedDrop <= 0 {
+		return errors.New("допустима втрата напруги має бути додатною")
+	}
+	return nil
+}
+
+// Зчитування файлу conductor_impedance.json та десеріалізація
+func loadConductorImpedance(filename string) ([]ConductorImpedanceData, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	bytes, err := io.ReadAll(file)
+	if err != nil {
+		return nil, err
+	}
+	var impedance []ConductorImpedanceData
+	err = json.Unmarshal(bytes, &impedance)
+	if err != nil {
+		return nil, err
+	}
+	return impedance, nil
+}
+
+// Погонні опори перерізу для матеріалу провідника
+func findSectionImpedance(
+	conductor string,
+	sech int,
+	impedanceData []ConductorImpedanceData,
+) *SectionImpedance {
+	for _, cd := range impedanceData {
+		if !strings.EqualFold(cd.Conductor, conductor) {
+			continue
+		}
+		for _, s := range cd.Sections {
+			if s.Sech == sech {
+				return &s
+			}
+		}
+	}
+	return nil
+}
+
+// Втрата напруги (%) та втрати потужності (кВт) у лінії з паралельних кабелів при струмі current одного кабелю
+func calculateVoltageDrop(current, voltage float64, z SectionImpedance, line LineParams) (float64, float64) {
+	sinPhi := math.Sqrt(1 - line.PowerFactor*line.PowerFactor)
+	drop := math.Sqrt(3.0) * current * line.Length * (z.R0*line.PowerFactor + z.X0*sinPhi) / (voltage * 1000.0) * 100.0
+	losses := float64(line.Cables) * 3.0 * current * current * z.R0 * line.Length / 1000.0
+	return drop, losses
+}
+
+// Перевірка обраного перерізу на втрату напруги зі збільшенням перерізу за потреби
+func checkVoltageDrop(
+	cableData *CableData,
+	sech int,
+	current float64,
+	voltage float64,
+	line LineParams,
+	impedanceData []ConductorImpedanceData,
+) (VoltageDropResult, error) {
+	// Перерізи цього типу кабелю, не менші за обраний, за зростанням
+	var sections []int
+	for _, c := range cableData.Cables {
+		if c.Sech >= sech {
+			sections = append(sections, c.Sech)
+		}
+	}
+	sort.Ints(sections)
+
+	var last VoltageDropResult
+	for _, s := range sections {
+		z := findSectionImpedance(cableData.Conductor, s, impedanceData)
+		if z == nil {
+			continue
+		}
+		drop, losses := calculateVoltageDrop(current, voltage, *z, line)
+		last = VoltageDropResult{
+			Sech:       s,
+			Impedance:  *z,
+			DropPct:    drop,
+			LossesKW:   losses,
+			Upsized:    s != sech,
+			Acceptable: drop <= line.AllowedDrop,
+		}
+		if last.Acceptable {
+			return last, nil
+		}
+	}
+	if last.Sech == 0 {
+		return last, fmt.Errorf("немає погонних опорів для кабелю %s перерізом %d мм²", cableData.Conductor, sech)
+	}
+	return last, nil
+}
+
+// Текстовий опис перевірки втрати напруги
+func describeVoltageDrop(selectedSech int, line LineParams, res VoltageDropResult) string {
+	text := fmt.Sprintf(
+		"Перевірка втрати напруги (L = %.2f км, cos φ = %.2f, кабелів: %d):\n"+
+			"  r0 = %.3f Ом/км, x0 = %.3f Ом/км (переріз %d мм²)\n"+
+			"  ΔU = %.2f %% (допустимо %.2f %%)\n"+
+			"  Втрати активної потужності ΔP = %.2f кВт\n",
+		line.Length, line.PowerFactor, line.Cables,
+		res.Impedance.R0, res.Impedance.X0, res.Sech,
+		res.DropPct, line.AllowedDrop,
+		res.LossesKW,
+	)
+	switch {
+	case !res.Acceptable:
+		text += "  УВАГА: втрата напруги перевищує допустиму навіть для найбільшого перерізу!\n"
+	case res.Upsized:
+		text += fmt.Sprintf("  Переріз збільшено з %d до %d мм² через втрату напруги\n", selectedSech, res.Sech)
+	default:
+		text += "  Втрата напруги в допустимих межах\n"
+	}
+	return text
+}